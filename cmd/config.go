@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/structs"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+// loadConfigFile loads the options of the given YAML config file into ko, overriding the ones already loaded.
+// Unknown keys are reported as errors rather than silently ignored, as they're most likely typos.
+func loadConfigFile(ko *koanf.Koanf, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// the options are decoded from JSON to reject unknown keys, which the YAML decoder doesn't support
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	var opts options.Options
+
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&opts); err != nil {
+		return fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+
+	if err := ko.Load(structs.Provider(opts, "yaml"), nil); err != nil {
+		return fmt.Errorf("failed to load config file %s: %w", path, err)
+	}
+
+	return nil
+}
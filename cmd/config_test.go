@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/knadh/koanf"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "kusk.yaml")
+	r.NoError(os.WriteFile(path, []byte(`
+host: example.com
+service:
+  name: webapp
+  namespace: books
+  port: 7000
+ingress:
+  tls:
+    secret_name: webapp-tls
+`), 0644))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("host", "", "")
+	fs.String("service.name", "", "")
+	fs.String("service.namespace", "default", "")
+	fs.Int32("service.port", 0, "")
+
+	r.NoError(fs.Parse([]string{"--service.port=8080"}))
+
+	ko := koanf.New(".")
+	r.NoError(loadConfigFile(ko, path))
+	r.NoError(ko.Load(flagsProvider(fs, ko), nil))
+
+	var opts options.Options
+	r.NoError(ko.UnmarshalWithConf("", &opts, koanf.UnmarshalConf{Tag: "yaml"}))
+
+	r.Equal("example.com", opts.Host)
+	r.Equal("webapp", opts.Service.Name)
+	// unchanged flags don't override the config file, even with a default value
+	r.Equal("books", opts.Service.Namespace)
+	// changed flags take precedence over the config file
+	r.Equal(int32(8080), opts.Service.Port)
+	r.Equal("webapp-tls", opts.Ingress.TLS.SecretName)
+}
+
+func TestLoadConfigFileUnknownKey(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "kusk.yaml")
+	r.NoError(os.WriteFile(path, []byte(`
+service:
+  name: webapp
+  prot: 7000
+`), 0644))
+
+	err := loadConfigFile(koanf.New("."), path)
+	r.EqualError(err, `failed to decode config file `+path+`: json: unknown field "prot"`)
+}
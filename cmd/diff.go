@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// lastAppliedAnnotationKey is set by kubectl apply on the live resources and never generated
+const lastAppliedAnnotationKey = "kubectl.kubernetes.io/last-applied-configuration"
+
+// newKubernetesClient returns a client for the current kubeconfig context, along with the namespace of the context
+func newKubernetesClient() (kubernetes.Interface, string, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	)
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the namespace of the current context: %w", err)
+	}
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return client, namespace, nil
+}
+
+// diffResources compares each generated resource with the live one of the same kind, name and namespace,
+// and returns a unified diff of their annotations, labels and spec.
+// Resources without a namespace are looked up in the given default namespace.
+func diffResources(ctx context.Context, client kubernetes.Interface, resources []runtime.Object, defaultNamespace string) (string, error) {
+	var builder strings.Builder
+
+	for _, resource := range resources {
+		metadata, err := meta.Accessor(resource)
+		if err != nil {
+			return "", err
+		}
+
+		kind := resource.GetObjectKind().GroupVersionKind().Kind
+
+		namespace := metadata.GetNamespace()
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+
+		id := fmt.Sprintf("%s %s/%s", kind, namespace, metadata.GetName())
+
+		live, err := getLiveResource(ctx, client, resource, namespace, metadata.GetName())
+		if errors.IsNotFound(err) {
+			fmt.Fprintf(&builder, "%s would be created\n", id)
+			continue
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to get %s: %w", id, err)
+		}
+
+		liveYAML, err := comparableYAML(live)
+		if err != nil {
+			return "", err
+		}
+
+		generatedYAML, err := comparableYAML(resource)
+		if err != nil {
+			return "", err
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(strings.TrimSuffix(liveYAML, "\n")),
+			B:        difflib.SplitLines(strings.TrimSuffix(generatedYAML, "\n")),
+			FromFile: "live " + id,
+			ToFile:   "generated " + id,
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to diff %s: %w", id, err)
+		}
+
+		if diff == "" {
+			fmt.Fprintf(&builder, "%s is up to date\n", id)
+			continue
+		}
+
+		builder.WriteString(diff)
+	}
+
+	return builder.String(), nil
+}
+
+// getLiveResource returns the live resource of the same type as the given one
+func getLiveResource(ctx context.Context, client kubernetes.Interface, resource runtime.Object, namespace, name string) (runtime.Object, error) {
+	switch resource.(type) {
+	case *networkingv1.Ingress:
+		return client.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	case *networkingv1beta1.Ingress:
+		return client.NetworkingV1beta1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	case *networkingv1.NetworkPolicy:
+		return client.NetworkingV1().NetworkPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("--diff doesn't support %s resources", resource.GetObjectKind().GroupVersionKind().Kind)
+	}
+}
+
+// comparableYAML marshals the parts of the resource set by the generators, leaving out the ones managed by the cluster
+func comparableYAML(resource runtime.Object) (string, error) {
+	metadata, err := meta.Accessor(resource)
+	if err != nil {
+		return "", err
+	}
+
+	annotations := map[string]string{}
+	for key, value := range metadata.GetAnnotations() {
+		if key != lastAppliedAnnotationKey {
+			annotations[key] = value
+		}
+	}
+
+	labels := map[string]string{}
+	for key, value := range metadata.GetLabels() {
+		labels[key] = value
+	}
+
+	var spec interface{}
+	switch r := resource.(type) {
+	case *networkingv1.Ingress:
+		spec = r.Spec
+	case *networkingv1beta1.Ingress:
+		spec = r.Spec
+	case *networkingv1.NetworkPolicy:
+		spec = r.Spec
+	}
+
+	b, err := yaml.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+			"labels":      labels,
+		},
+		"spec": spec,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resource: %w", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestIngress(namespace, servicePort string, annotations map[string]string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "webapp-ingress",
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "webapp",
+											Port: networkingv1.ServiceBackendPort{Name: servicePort},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffResources(t *testing.T) {
+	testCases := []struct {
+		name      string
+		live      []runtime.Object
+		generated runtime.Object
+		res       string
+	}{
+		{
+			name:      "not found",
+			generated: newTestIngress("default", "http", nil),
+			res:       "Ingress default/webapp-ingress would be created\n",
+		},
+		{
+			name: "up to date",
+			live: []runtime.Object{
+				newTestIngress("default", "http", map[string]string{
+					lastAppliedAnnotationKey: "{}",
+				}),
+			},
+			generated: newTestIngress("default", "http", nil),
+			res:       "Ingress default/webapp-ingress is up to date\n",
+		},
+		{
+			name:      "namespace defaults to the context one",
+			live:      []runtime.Object{newTestIngress("books", "http", nil)},
+			generated: newTestIngress("", "http", nil),
+			res:       "Ingress books/webapp-ingress is up to date\n",
+		},
+		{
+			name: "changed",
+			live: []runtime.Object{newTestIngress("default", "http", nil)},
+			generated: newTestIngress("default", "web", map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-redirect": "true",
+			}),
+			res: `--- live Ingress default/webapp-ingress
++++ generated Ingress default/webapp-ingress
+@@ -1,5 +1,6 @@
+ metadata:
+-  annotations: {}
++  annotations:
++    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+   labels: {}
+ spec:
+   rules:
+@@ -9,5 +10,5 @@
+           service:
+             name: webapp
+             port:
+-              name: http
++              name: web
+         path: /
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			client := fake.NewSimpleClientset(testCase.live...)
+
+			res, err := diffResources(context.Background(), client, []runtime.Object{testCase.generated}, "books")
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/posflag"
+	"github.com/spf13/pflag"
+
+	"github.com/kubeshop/kusk/generators"
+)
+
+// flagsProvider returns a koanf provider for the given flag set.
+// Unlike the plain posflag provider, it preserves the types of map flags
+// so that keys containing the delimiter (i.e. annotation names) are kept intact.
+// Flags left at their zero default value are skipped, so optional (pointer) options
+// such as cors.credentials stay unset unless passed explicitly.
+// Flags annotated with generators.OptionKeyAnnotation set the option of the annotated key instead of their name.
+func flagsProvider(fs *pflag.FlagSet, ko *koanf.Koanf) *posflag.Posflag {
+	return posflag.ProviderWithValue(fs, ".", ko, func(key string, value string) (string, interface{}) {
+		f := fs.Lookup(key)
+		if !f.Changed && isZeroDefault(f) {
+			return "", nil
+		}
+
+		if keys := f.Annotations[generators.OptionKeyAnnotation]; len(keys) > 0 {
+			key = keys[0]
+		}
+
+		return key, flagValue(fs, f)
+	})
+}
+
+func isZeroDefault(f *pflag.Flag) bool {
+	switch f.DefValue {
+	case "", "0", "false", "[]":
+		return true
+	default:
+		return false
+	}
+}
+
+func flagValue(fs *pflag.FlagSet, f *pflag.Flag) interface{} {
+	switch f.Value.Type() {
+	case "bool":
+		val, _ := fs.GetBool(f.Name)
+		return val
+	case "stringSlice":
+		val, _ := fs.GetStringSlice(f.Name)
+		return val
+	case "stringArray":
+		val, _ := fs.GetStringArray(f.Name)
+		return val
+	case "stringToString":
+		val, _ := fs.GetStringToString(f.Name)
+		return val
+	default:
+		// the rest of the types are decoded from their string representation
+		return f.Value.String()
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/knadh/koanf"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+func TestFlagsProvider(t *testing.T) {
+	r := require.New(t)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("host", "", "")
+	fs.Int32("service.port", 80, "")
+	fs.StringSlice("ingress.tls.hosts", []string{}, "")
+	fs.StringToString("ingress.annotations", map[string]string{}, "")
+	fs.StringArray("apisix.plugins", []string{}, "")
+
+	err := fs.Parse([]string{
+		`--apisix.plugins=cors={"allow_origins":"*","allow_methods":"GET,POST"}`,
+		"--host=example.com",
+		"--ingress.tls.hosts=example.com,www.example.com",
+		"--ingress.annotations=nginx.ingress.kubernetes.io/rewrite-target=/",
+		"--ingress.annotations=nginx.ingress.kubernetes.io/ssl-redirect=false",
+	})
+	r.NoError(err)
+
+	ko := koanf.New(".")
+	r.NoError(ko.Load(flagsProvider(fs, ko), nil))
+
+	var opts options.Options
+	r.NoError(ko.UnmarshalWithConf("", &opts, koanf.UnmarshalConf{Tag: "yaml"}))
+
+	r.Equal("example.com", opts.Host)
+	r.Equal(int32(80), opts.Service.Port)
+	r.Equal([]string{"example.com", "www.example.com"}, opts.Ingress.TLS.Hosts)
+	r.Equal(map[string]string{
+		"nginx.ingress.kubernetes.io/rewrite-target": "/",
+		"nginx.ingress.kubernetes.io/ssl-redirect":   "false",
+	}, opts.Ingress.Annotations)
+	r.Equal([]string{`cors={"allow_origins":"*","allow_methods":"GET,POST"}`}, opts.APISIX.Plugins)
+}
+
+func TestFlagsProviderSkipsZeroDefaults(t *testing.T) {
+	r := require.New(t)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Bool("cors.credentials", false, "")
+	fs.StringSlice("cors.origins", []string{}, "")
+	fs.Int32("service.port", 80, "")
+
+	r.NoError(fs.Parse([]string{}))
+
+	ko := koanf.New(".")
+	r.NoError(ko.Load(flagsProvider(fs, ko), nil))
+
+	var opts options.Options
+	r.NoError(ko.UnmarshalWithConf("", &opts, koanf.UnmarshalConf{Tag: "yaml"}))
+
+	r.Nil(opts.CORS.Credentials)
+	r.Empty(opts.CORS.Origins)
+	r.Equal(int32(80), opts.Service.Port)
+
+	r.NoError(fs.Parse([]string{"--cors.credentials=false"}))
+
+	ko = koanf.New(".")
+	r.NoError(ko.Load(flagsProvider(fs, ko), nil))
+	r.NoError(ko.UnmarshalWithConf("", &opts, koanf.UnmarshalConf{Tag: "yaml"}))
+
+	r.NotNil(opts.CORS.Credentials)
+	r.False(*opts.CORS.Credentials)
+}
+
+func TestFlagsProviderOptionKeyAnnotation(t *testing.T) {
+	r := require.New(t)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("ingress.affinity", "", "")
+	r.NoError(fs.SetAnnotation("ingress.affinity", generators.OptionKeyAnnotation, []string{"ingress.affinity.type"}))
+	fs.String("ingress.affinity.cookie.name", "", "")
+
+	r.NoError(fs.Parse([]string{
+		"--ingress.affinity=cookie",
+		"--ingress.affinity.cookie.name=route",
+	}))
+
+	ko := koanf.New(".")
+	r.NoError(ko.Load(flagsProvider(fs, ko), nil))
+
+	var opts options.Options
+	r.NoError(ko.UnmarshalWithConf("", &opts, koanf.UnmarshalConf{Tag: "yaml"}))
+
+	r.Equal("cookie", opts.Ingress.Affinity.Type)
+	r.Equal("route", opts.Ingress.Affinity.Cookie.Name)
+}
+
+func TestNginxIngressGenerateFlags(t *testing.T) {
+	testCases := []struct {
+		name  string
+		args  []string
+		check func(r *require.Assertions, opts options.Options)
+	}{
+		{
+			name: "service",
+			args: []string{
+				"--generate-service",
+				"--service.selector=app.kubernetes.io/name=webapp",
+			},
+			check: func(r *require.Assertions, opts options.Options) {
+				r.True(opts.Service.Generate)
+				r.Equal(map[string]string{"app.kubernetes.io/name": "webapp"}, opts.Service.Selector)
+			},
+		},
+		{
+			name: "network policy",
+			args: []string{
+				"--generate-network-policy",
+				"--network-policy.pod-selector=app.kubernetes.io/name=webapp",
+			},
+			check: func(r *require.Assertions, opts options.Options) {
+				r.True(opts.NetworkPolicy.Generate)
+				r.Equal(map[string]string{"app.kubernetes.io/name": "webapp"}, opts.NetworkPolicy.PodSelector)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			fs := generators.Registry["ingress-nginx"].Flags()
+			r.NoError(fs.Parse(testCase.args))
+
+			ko := koanf.New(".")
+			r.NoError(ko.Load(flagsProvider(fs, ko), nil))
+
+			var opts options.Options
+			r.NoError(ko.UnmarshalWithConf("", &opts, koanf.UnmarshalConf{Tag: "yaml"}))
+
+			testCase.check(r, opts)
+		})
+	}
+}
@@ -1,18 +1,27 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/knadh/koanf"
-	"github.com/knadh/koanf/providers/posflag"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/kubeshop/kusk/generators"
 	_ "github.com/kubeshop/kusk/generators/ambassador/v1"
 	_ "github.com/kubeshop/kusk/generators/ambassador/v2"
+	_ "github.com/kubeshop/kusk/generators/apisix"
+	_ "github.com/kubeshop/kusk/generators/contour"
+	_ "github.com/kubeshop/kusk/generators/gateway"
+	_ "github.com/kubeshop/kusk/generators/gloo"
+	_ "github.com/kubeshop/kusk/generators/istio"
+	_ "github.com/kubeshop/kusk/generators/kong"
 	_ "github.com/kubeshop/kusk/generators/linkerd"
 	_ "github.com/kubeshop/kusk/generators/nginx_ingress"
 	_ "github.com/kubeshop/kusk/generators/traefik"
@@ -25,20 +34,95 @@ var (
 	// each flag can then override settings
 	k = koanf.New(".")
 
-	apiSpecPath string
+	defaultFetchTimeout = 30 * time.Second
+
+	apiSpecPath  string
+	fetchTimeout time.Duration
+	configPath   string
+
+	outputPath   string
+	outputDir    string
+	forceOutput  bool
+	validateOnly bool
+
+	noLeadingSeparator bool
+	outputFormat       string
+
+	diffMode bool
+
+	mergeFrom string
+
+	listPathsMode bool
+
+	helmChartDir    string
+	helmChartValues bool
+
+	kustomizeDir string
+
+	outputIndent    int
+	outputOmitEmpty bool
+
+	verbose bool
+
+	skipDeprecated bool
 )
 
-func getOptions() (*options.Options, error) {
-	var res options.Options
+const (
+	outputFormatYAML = "yaml"
+	outputFormatJSON = "json"
+)
+
+// loadOptions loads the options into ko in layers: the defaults of the generator first, overridden by the x-kusk extension
+// at the root of the spec, then by the given config file, if any, then by the flags
+func loadOptions(ko *koanf.Koanf, gen generators.Interface, fs *pflag.FlagSet, apiSpec *openapi3.T, configPath string) (*options.Options, error) {
+	// start from the generator defaults
+	if err := ko.Load(structs.Provider(generators.Defaults(gen), "yaml"), nil); err != nil {
+		return nil, err
+	}
 
-	err := k.UnmarshalWithConf("", &res, koanf.UnmarshalConf{Tag: "yaml"})
+	// parse x-kusk top-level extension
+	kuskExtensionOpts, err := spec.GetOptions(apiSpec)
 	if err != nil {
+		return nil, err
+	}
+
+	// populate koanf object with the extension content
+	if err := ko.Load(structs.Provider(*kuskExtensionOpts, "yaml"), nil); err != nil {
+		return nil, err
+	}
+
+	// override the extension options with the config file ones
+	if configPath != "" {
+		if err := loadConfigFile(ko, configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// override koanf options with user-provided flags
+	if err := ko.Load(flagsProvider(fs, ko), nil); err != nil {
+		return nil, err
+	}
+
+	// fetch merged options
+	var res options.Options
+	if err := ko.UnmarshalWithConf("", &res, koanf.UnmarshalConf{Tag: "yaml"}); err != nil {
 		return nil, fmt.Errorf("failed to decode options: %w", err)
 	}
 
+	res.PathSubOptions = kuskExtensionOpts.PathSubOptions
+	res.OperationSubOptions = kuskExtensionOpts.OperationSubOptions
+
 	return &res, nil
 }
 
+// newSpecLoader returns an OpenAPI spec loader fetching remote specs with the given timeout
+func newSpecLoader(timeout time.Duration) *openapi3.Loader {
+	loader := openapi3.NewLoader()
+	loader.ReadFromURIFunc = spec.ReadFromURIWithTimeout(timeout)
+
+	return loader
+}
+
 func init() {
 	addGenerator := func(gen generators.Interface) {
 		cmd := &cobra.Command{
@@ -50,44 +134,220 @@ func init() {
 					log.Fatal(fmt.Errorf("no openapi or swagger definition provided"))
 				}
 
+				if outputFormat != outputFormatYAML && outputFormat != outputFormatJSON {
+					log.Fatal(fmt.Errorf("unsupported output format %s: only yaml and json are supported", outputFormat))
+				}
+
+				format := generators.FormatOptions{
+					Indent:    outputIndent,
+					OmitEmpty: outputOmitEmpty,
+				}
+
+				if outputFormat == outputFormatJSON && !format.IsDefault() {
+					log.Fatal(fmt.Errorf("--indent and --omit-empty can't be used with --output-format json"))
+				}
+
 				// parse OpenAPI spec
-				apiSpec, err := spec.NewParser(openapi3.NewLoader()).Parse(apiSpecPath)
+				apiSpec, err := spec.NewParser(newSpecLoader(fetchTimeout)).Parse(apiSpecPath)
 				if err != nil {
 					log.Fatal(err)
 				}
 
-				// parse x-kusk top-level extension
-				kuskExtensionOpts, err := spec.GetOptions(apiSpec)
+				opts, err := loadOptions(k, gen, cmd.Flags(), apiSpec, configPath)
 				if err != nil {
 					log.Fatal(err)
 				}
 
-				// populate koanf object with the extension content
-				err = k.Load(structs.Provider(*kuskExtensionOpts, "yaml"), nil)
-				if err != nil {
-					log.Fatal(err)
+				if verbose {
+					opts.Logger = newWriterLogger(os.Stderr, 1)
 				}
 
-				// override koanf options with user-provided flags
-				err = k.Load(posflag.Provider(cmd.Flags(), ".", k), nil)
-				if err != nil {
-					log.Fatal(err)
+				if skipDeprecated {
+					spec.DisableDeprecatedPaths(opts, apiSpec)
 				}
 
-				// fetch merged options
-				opts, err := getOptions()
-				if err != nil {
+				if err := spec.SelectEnvironmentServers(apiSpec, opts.Environment); err != nil {
 					log.Fatal(err)
 				}
 
-				opts.PathSubOptions = kuskExtensionOpts.PathSubOptions
-				opts.OperationSubOptions = kuskExtensionOpts.OperationSubOptions
+				if validateOnly {
+					if problems := validate(gen, opts, apiSpec); len(problems) > 0 {
+						fmt.Fprint(os.Stderr, validationReport(problems))
+						os.Exit(1)
+					}
+
+					return
+				}
+
+				if listPathsMode {
+					if outputPath != "" || outputDir != "" || diffMode || mergeFrom != "" || helmChartDir != "" || kustomizeDir != "" {
+						log.Fatal(fmt.Errorf("--list-paths can't be used with --output, --output-dir, --diff, --merge-from, --helm-chart or --kustomize"))
+					}
+
+					res, err := listPaths(gen, opts, apiSpec)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					fmt.Print(res)
+
+					return
+				}
+
+				if diffMode {
+					if outputPath != "" || outputDir != "" || mergeFrom != "" || helmChartDir != "" || kustomizeDir != "" {
+						log.Fatal(fmt.Errorf("--diff can't be used with --output, --output-dir, --merge-from, --helm-chart or --kustomize"))
+					}
+
+					resourcesGen, ok := gen.(generators.ResourcesGenerator)
+					if !ok {
+						log.Fatal(fmt.Errorf("%s generator doesn't support --diff", gen.Cmd()))
+					}
+
+					resources, err := resourcesGen.GenerateResources(opts, apiSpec)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					client, namespace, err := newKubernetesClient()
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					diff, err := diffResources(context.Background(), client, resources, namespace)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					fmt.Print(diff)
+
+					return
+				}
+
+				if helmChartDir != "" {
+					if outputPath != "" || outputDir != "" || mergeFrom != "" || kustomizeDir != "" {
+						log.Fatal(fmt.Errorf("--helm-chart can't be used with --output, --output-dir, --merge-from or --kustomize"))
+					}
+
+					if outputFormat == outputFormatJSON {
+						log.Fatal(fmt.Errorf("--output-format json can't be used with --helm-chart"))
+					}
+
+					chart, err := generateHelmChart(gen, opts, apiSpec, helmChartValues, format)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					if noLeadingSeparator {
+						chart.templates.TrimLeadingSeparator()
+					}
+
+					if err := chart.write(helmChartDir, forceOutput); err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				if kustomizeDir != "" {
+					if outputPath != "" || outputDir != "" || mergeFrom != "" {
+						log.Fatal(fmt.Errorf("--kustomize can't be used with --output, --output-dir or --merge-from"))
+					}
+
+					if outputFormat == outputFormatJSON {
+						log.Fatal(fmt.Errorf("--output-format json can't be used with --kustomize"))
+					}
+
+					files, err := generateKustomization(gen, opts, apiSpec)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					if err := files.Format(format); err != nil {
+						log.Fatal(err)
+					}
+
+					if noLeadingSeparator {
+						files.TrimLeadingSeparator()
+					}
+
+					if err := files.Write(kustomizeDir, forceOutput); err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				if outputDir != "" {
+					if mergeFrom != "" {
+						log.Fatal(fmt.Errorf("--merge-from can't be used with --output-dir"))
+					}
+
+					if outputPath != "" {
+						log.Fatal(fmt.Errorf("--output and --output-dir can't be used together"))
+					}
+
+					if outputFormat == outputFormatJSON {
+						log.Fatal(fmt.Errorf("--output-format json can't be used with --output-dir"))
+					}
+
+					filesGen, ok := gen.(generators.FilesGenerator)
+					if !ok {
+						log.Fatal(fmt.Errorf("%s generator doesn't support --output-dir", gen.Cmd()))
+					}
 
-				res, err := gen.Generate(opts, apiSpec)
+					files, err := filesGen.GenerateFiles(opts, apiSpec)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					if err := files.Format(format); err != nil {
+						log.Fatal(err)
+					}
+
+					if noLeadingSeparator {
+						files.TrimLeadingSeparator()
+					}
+
+					if err := files.Write(outputDir, forceOutput); err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				var res string
+				if mergeFrom != "" {
+					res, err = generateMerged(gen, opts, apiSpec, mergeFrom)
+				} else {
+					res, err = gen.Generate(opts, apiSpec)
+				}
 				if err != nil {
 					log.Fatal(err)
 				}
 
+				if outputFormat == outputFormatJSON {
+					if res, err = generators.ToJSONList(res); err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if res, err = generators.Format(res, format); err != nil {
+						log.Fatal(err)
+					}
+
+					if noLeadingSeparator {
+						res = generators.TrimLeadingSeparator(res)
+					}
+				}
+
+				if outputPath != "" {
+					if err := writeOutput(outputPath, res, forceOutput); err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
 				fmt.Println(res)
 			},
 		}
@@ -113,14 +373,160 @@ func addGlobalFlags(cmd *cobra.Command) {
 		"in",
 		"i",
 		"",
-		"file path to api spec file to generate mappings from. e.g. --in apispec.yaml",
+		"file path or http(s) URL to api spec file to generate mappings from. e.g. --in apispec.yaml",
 	)
 	cmd.MarkFlagRequired("in")
 
+	cmd.Flags().DurationVar(
+		&fetchTimeout,
+		"timeout",
+		defaultFetchTimeout,
+		"timeout for fetching the api spec from a URL",
+	)
+
+	cmd.Flags().StringVar(
+		&configPath,
+		"config",
+		"",
+		"file path to a YAML file of options, overriding the x-kusk extension ones and overridden by flags",
+	)
+
+	cmd.Flags().StringVarP(
+		&outputPath,
+		"output",
+		"o",
+		"",
+		"file path to write generated resources to instead of stdout",
+	)
+
+	cmd.Flags().StringVar(
+		&outputDir,
+		"output-dir",
+		"",
+		"directory to write each generated resource to as a separate file, where supported",
+	)
+
+	cmd.Flags().BoolVar(
+		&validateOnly,
+		"validate-only",
+		false,
+		"only validate the spec and options without generating resources, problems are reported with a non-zero exit code",
+	)
+
+	cmd.Flags().BoolVarP(
+		&verbose,
+		"verbose",
+		"v",
+		false,
+		"log the generation decisions to stderr, e.g. why a path was left out, where supported",
+	)
+
+	cmd.Flags().BoolVar(
+		&skipDeprecated,
+		"skip-deprecated",
+		false,
+		"leave out the paths whose operations are all deprecated",
+	)
+
+	cmd.Flags().BoolVar(
+		&diffMode,
+		"diff",
+		false,
+		"compare the generated resources with the live ones of the current kubeconfig context instead of printing them, where supported",
+	)
+
+	cmd.Flags().BoolVar(
+		&listPathsMode,
+		"list-paths",
+		false,
+		"only list the included spec paths along with the path, path type and name of their route, where supported",
+	)
+
+	cmd.Flags().StringVar(
+		&mergeFrom,
+		"merge-from",
+		"",
+		"file path to an existing manifest to merge the generated Ingresses into, preserving the fields kusk doesn't manage, where supported",
+	)
+
+	cmd.Flags().StringVar(
+		&helmChartDir,
+		"helm-chart",
+		"",
+		"directory to write a Helm chart to, with each generated resource as a separate file of its templates directory, where supported",
+	)
+
+	cmd.Flags().BoolVar(
+		&helmChartValues,
+		"helm-values",
+		true,
+		"reference the host, class and Service of the generated Ingresses from the values.yaml of the --helm-chart",
+	)
+
+	cmd.Flags().StringVar(
+		&kustomizeDir,
+		"kustomize",
+		"",
+		"directory to write a kustomize base to, with each generated resource as a separate file listed by its kustomization.yaml, where supported",
+	)
+
+	cmd.Flags().BoolVar(
+		&forceOutput,
+		"force",
+		false,
+		"overwrite output files if they already exist",
+	)
+
+	cmd.Flags().StringVar(
+		&outputFormat,
+		"output-format",
+		outputFormatYAML,
+		"format of the generated resources: yaml, or json for a List wrapping the resources",
+	)
+
+	cmd.Flags().IntVar(
+		&outputIndent,
+		"indent",
+		0,
+		"number of spaces to indent the generated YAML with, sequences included, which are otherwise kept at the indentation of their key",
+	)
+
+	cmd.Flags().BoolVar(
+		&outputOmitEmpty,
+		"omit-empty",
+		false,
+		"leave out the empty collections of the generated YAML instead of rendering them as {} or [], e.g. resources: {}",
+	)
+
+	cmd.Flags().BoolVar(
+		&noLeadingSeparator,
+		"no-leading-separator",
+		false,
+		"omit the --- separator before the first generated resource",
+	)
+
 	cmd.Flags().String(
 		"namespace",
-		"default",
-		"namespace for generated resources",
+		"",
+		"namespace for generated resources, omitted when empty",
+	)
+
+	cmd.Flags().String(
+		"host_template",
+		"",
+		"a Go template rendered against the options to set the host when --host isn't set, e.g. {{.Service.Name}}.{{.Namespace}}.example.com",
+	)
+
+	cmd.Flags().String(
+		"environment",
+		"",
+		"use only the spec servers tagged with this environment in their x-kusk extension, e.g. production, to derive the hosts and base path",
+	)
+
+	cmd.Flags().String(
+		"name_prefix",
+		"",
+		"a prefix prepended to the names of the generated resources, e.g. tenant-",
 	)
 
 	cmd.Flags().String(
@@ -137,7 +543,19 @@ func addGlobalFlags(cmd *cobra.Command) {
 
 	cmd.Flags().Int32(
 		"service.port",
-		80,
-		"target Service port",
+		0,
+		"target Service port, 80 if neither service.port nor service.port_name is set",
+	)
+
+	cmd.Flags().StringSlice(
+		"path.exclude",
+		[]string{},
+		"a glob pattern of spec paths to leave out, e.g. /healthz or /internal/* to include subpaths, can be repeated",
+	)
+
+	cmd.Flags().StringSlice(
+		"path.include",
+		[]string{},
+		"a glob pattern of spec paths to restrict generation to, with the same syntax as path.exclude, can be repeated",
 	)
 }
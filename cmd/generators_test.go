@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/knadh/koanf"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+// defaultsGenerator is an ingress-nginx generator with option defaults of its own
+type defaultsGenerator struct {
+	generators.Interface
+}
+
+func (g defaultsGenerator) Defaults() options.Options {
+	return options.Options{
+		Host: "example.com",
+		Path: options.PathOptions{
+			Type: "Prefix",
+		},
+		Service: options.ServiceOptions{
+			Port: 8080,
+		},
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  host: books.example.com
+  service:
+    name: webapp
+  path:
+    base: /api
+paths:
+  /books:
+    x-kusk:
+      disabled: true
+    get: {}
+`))
+	r.NoError(err)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("host", "", "")
+	fs.String("path.base", "/", "")
+
+	r.NoError(fs.Parse([]string{"--host=webapp.example.com"}))
+
+	opts, err := loadOptions(koanf.New("."), generators.Registry["ingress-nginx"], fs, apiSpec, "")
+	r.NoError(err)
+
+	// changed flags take precedence over the x-kusk extension
+	r.Equal("webapp.example.com", opts.Host)
+	// unchanged flags don't, even with a default value
+	r.Equal("/api", opts.Path.Base)
+	r.Equal("webapp", opts.Service.Name)
+	r.True(opts.IsPathDisabled("/books"))
+}
+
+func TestLoadOptionsGeneratorDefaults(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  host: books.example.com
+paths:
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("path.type", "", "")
+	fs.Int32("service.port", 80, "")
+
+	r.NoError(fs.Parse([]string{"--service.port=7000"}))
+
+	gen := defaultsGenerator{generators.Registry["ingress-nginx"]}
+
+	opts, err := loadOptions(koanf.New("."), gen, fs, apiSpec, "")
+	r.NoError(err)
+
+	// the generator defaults apply to the options set nowhere else
+	r.Equal("Prefix", opts.Path.Type)
+	// and are overridden by the x-kusk extension and the flags
+	r.Equal("books.example.com", opts.Host)
+	r.Equal(int32(7000), opts.Service.Port)
+}
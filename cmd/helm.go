@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	helmChartAPIVersion = "v2"
+	helmChartVersion    = "0.1.0"
+
+	// helmValuePlaceholderPrefix marks the values of the marshalled resources to replace with a reference to the chart values
+	helmValuePlaceholderPrefix = "kusk-helm-value-"
+)
+
+// helmChart is a Helm chart of the generated resources
+type helmChart struct {
+	// templates are the files of the templates directory, the generated resources
+	// with the values of the Ingresses replaced by references to the chart values
+	templates generators.Files
+
+	// values is the content of values.yaml, holding the referenced values
+	values string
+
+	// chart is the content of Chart.yaml
+	chart string
+}
+
+// generateHelmChart generates the resources as Helm chart templates, each one into a separate file.
+// With withValues, the host, class and target Service of the Ingresses reference the chart values,
+// which default to the generated ones. The templates are serialized with the given format options.
+func generateHelmChart(
+	gen generators.Interface,
+	opts *options.Options,
+	apiSpec *openapi3.T,
+	withValues bool,
+	format generators.FormatOptions,
+) (*helmChart, error) {
+	filesGen, ok := gen.(generators.FilesGenerator)
+	if !ok {
+		return nil, fmt.Errorf("%s generator doesn't support --helm-chart", gen.Cmd())
+	}
+
+	files, err := filesGen.GenerateFiles(opts, apiSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	// the values are taken from the first Ingress they're found in, the files being sorted to keep it stable
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	values := helmValues{}
+	templates := generators.Files{}
+	for _, fileName := range fileNames {
+		if !withValues {
+			template, err := generators.Format(files[fileName], format)
+			if err != nil {
+				return nil, fmt.Errorf("failed to format %s: %w", fileName, err)
+			}
+
+			templates[fileName] = template
+			continue
+		}
+
+		template, err := values.templatize(files[fileName], format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template %s: %w", fileName, err)
+		}
+
+		templates[fileName] = template
+	}
+
+	valuesYAML, err := values.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	chart := map[string]interface{}{
+		"apiVersion": helmChartAPIVersion,
+		"name":       opts.Service.Name,
+		"type":       "application",
+		"version":    helmChartVersion,
+	}
+
+	if apiSpec.Info != nil {
+		if apiSpec.Info.Title != "" {
+			chart["description"] = apiSpec.Info.Title
+		}
+
+		if apiSpec.Info.Version != "" {
+			chart["appVersion"] = apiSpec.Info.Version
+		}
+	}
+
+	chartYAML, err := yaml.Marshal(chart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Chart.yaml: %w", err)
+	}
+
+	return &helmChart{
+		templates: templates,
+		values:    valuesYAML,
+		chart:     string(chartYAML),
+	}, nil
+}
+
+// write writes the chart into the given directory, creating it if needed.
+// Existing files are only overwritten when force is set.
+func (c *helmChart) write(dir string, force bool) error {
+	if err := c.templates.Write(filepath.Join(dir, "templates"), force); err != nil {
+		return err
+	}
+
+	if err := writeOutput(filepath.Join(dir, "values.yaml"), c.values, force); err != nil {
+		return err
+	}
+
+	return writeOutput(filepath.Join(dir, "Chart.yaml"), c.chart, force)
+}
+
+// helmValues are the chart values, by key, e.g. service.name
+type helmValues map[string]interface{}
+
+// templatize replaces the host, class and backend Service values of the Ingresses of the given generated output
+// by references to the chart values. A value different from the one first found under the same key,
+// e.g. the class of a path overriding it, is left as is.
+// The resources are formatted before the references are set, as they aren't valid YAML.
+func (v helmValues) templatize(output string, format generators.FormatOptions) (string, error) {
+	var builder strings.Builder
+
+	for _, document := range generators.SplitDocuments(output) {
+		var resource map[string]interface{}
+		if err := yaml.Unmarshal([]byte(document), &resource); err != nil {
+			return "", err
+		}
+
+		if resource["kind"] == "Ingress" {
+			if spec, ok := resource["spec"].(map[string]interface{}); ok {
+				v.templatizeIngressSpec(spec)
+			}
+		}
+
+		b, err := yaml.Marshal(resource)
+		if err != nil {
+			return "", err
+		}
+
+		formatted, err := generators.Format(string(b), format)
+		if err != nil {
+			return "", err
+		}
+
+		builder.WriteString(generators.DocumentSeparator)
+		builder.WriteString(v.replacePlaceholders(generators.TrimLeadingSeparator(formatted)))
+	}
+
+	return builder.String(), nil
+}
+
+func (v helmValues) templatizeIngressSpec(spec map[string]interface{}) {
+	v.replace(spec, "ingressClassName", "ingress.class")
+
+	// networking.k8s.io/v1beta1 Ingresses set the default backend as backend
+	for _, field := range []string{"defaultBackend", "backend"} {
+		if backend, ok := spec[field].(map[string]interface{}); ok {
+			v.templatizeBackend(backend)
+		}
+	}
+
+	rules, _ := spec["rules"].([]interface{})
+	for _, rule := range rules {
+		rule, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		v.replace(rule, "host", "host")
+
+		http, _ := rule["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for _, path := range paths {
+			if path, ok := path.(map[string]interface{}); ok {
+				if backend, ok := path["backend"].(map[string]interface{}); ok {
+					v.templatizeBackend(backend)
+				}
+			}
+		}
+	}
+
+	tls, _ := spec["tls"].([]interface{})
+	for _, entry := range tls {
+		entry, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		hosts, _ := entry["hosts"].([]interface{})
+		for i := range hosts {
+			if placeholder, ok := v.placeholder("host", hosts[i]); ok {
+				hosts[i] = placeholder
+			}
+		}
+	}
+}
+
+func (v helmValues) templatizeBackend(backend map[string]interface{}) {
+	// networking.k8s.io/v1beta1 backends
+	v.replace(backend, "serviceName", "service.name")
+	v.replace(backend, "servicePort", "service.port")
+
+	service, ok := backend["service"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	v.replace(service, "name", "service.name")
+
+	if port, ok := service["port"].(map[string]interface{}); ok {
+		v.replace(port, "number", "service.port")
+	}
+}
+
+// replace replaces the value of the given field, if set, by the placeholder of the value under key
+func (v helmValues) replace(fields map[string]interface{}, field, key string) {
+	value, ok := fields[field]
+	if !ok || value == "" {
+		return
+	}
+
+	if placeholder, ok := v.placeholder(key, value); ok {
+		fields[field] = placeholder
+	}
+}
+
+// placeholder returns the placeholder of the value under key, recording the value if none is yet.
+// No placeholder is returned for a value different from the recorded one.
+func (v helmValues) placeholder(key string, value interface{}) (string, bool) {
+	if recorded, ok := v[key]; ok && recorded != value {
+		return "", false
+	}
+
+	v[key] = value
+
+	return helmValuePlaceholderPrefix + key, true
+}
+
+// replacePlaceholders replaces the placeholders of the marshalled resource by references to the chart values,
+// quoting the strings so that they're still rendered as strings, e.g. a numeric host
+func (v helmValues) replacePlaceholders(output string) string {
+	keys := v.sortedKeys()
+
+	// replace the longest keys first, as a key may prefix another one
+	sort.SliceStable(keys, func(i, j int) bool {
+		return len(keys[i]) > len(keys[j])
+	})
+
+	for _, key := range keys {
+		reference := "{{ .Values." + key + " }}"
+		if _, ok := v[key].(string); ok {
+			reference = "{{ .Values." + key + " | quote }}"
+		}
+
+		output = strings.ReplaceAll(output, helmValuePlaceholderPrefix+key, reference)
+	}
+
+	return output
+}
+
+// marshal returns the values.yaml content, nesting the values by the segments of their keys
+func (v helmValues) marshal() (string, error) {
+	values := map[string]interface{}{}
+
+	for _, key := range v.sortedKeys() {
+		parent := values
+
+		segments := strings.Split(key, ".")
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := parent[segment].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[segment] = child
+			}
+
+			parent = child
+		}
+
+		parent[segments[len(segments)-1]] = v[key]
+	}
+
+	b, err := yaml.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal values.yaml: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func (v helmValues) sortedKeys() []string {
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
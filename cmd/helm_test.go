@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+func TestGenerateHelmChart(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /authors:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := &options.Options{
+		Host: "books.example.com",
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Path: options.PathOptions{
+			Base:  "/api",
+			Split: true,
+		},
+		Ingress: options.IngressOptions{
+			Class: "nginx",
+		},
+	}
+
+	chart, err := generateHelmChart(generators.Registry["ingress-nginx"], opts, apiSpec, true, generators.FormatOptions{})
+	r.NoError(err)
+
+	dir := t.TempDir()
+	r.NoError(chart.write(dir, false))
+
+	templates, err := os.ReadDir(filepath.Join(dir, "templates"))
+	r.NoError(err)
+
+	var templateNames []string
+	for _, template := range templates {
+		templateNames = append(templateNames, template.Name())
+	}
+	sort.Strings(templateNames)
+	r.Equal([]string{"webapp-authors.yaml", "webapp-books.yaml"}, templateNames)
+
+	template, err := os.ReadFile(filepath.Join(dir, "templates", "webapp-books.yaml"))
+	r.NoError(err)
+	r.Contains(string(template), "ingressClassName: {{ .Values.ingress.class | quote }}\n")
+	r.Contains(string(template), "- host: {{ .Values.host | quote }}\n")
+	r.Contains(string(template), "name: {{ .Values.service.name | quote }}\n")
+	r.Contains(string(template), "number: {{ .Values.service.port }}\n")
+	r.Contains(string(template), "path: /api/books\n")
+
+	values, err := os.ReadFile(filepath.Join(dir, "values.yaml"))
+	r.NoError(err)
+	r.Equal(`host: books.example.com
+ingress:
+  class: nginx
+service:
+  name: webapp
+  port: 80
+`, string(values))
+
+	chartYAML, err := os.ReadFile(filepath.Join(dir, "Chart.yaml"))
+	r.NoError(err)
+	r.Equal(`apiVersion: v2
+appVersion: 1.0.0
+description: Books
+name: webapp
+type: application
+version: 0.1.0
+`, string(chartYAML))
+}
+
+func TestGenerateHelmChartUnsupported(t *testing.T) {
+	r := require.New(t)
+
+	_, err := generateHelmChart(generators.Registry["linkerd"], &options.Options{}, &openapi3.T{}, true, generators.FormatOptions{})
+	r.Error(err)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	kustomizationFileName   = "kustomization.yaml"
+	kustomizationAPIVersion = "kustomize.config.k8s.io/v1beta1"
+)
+
+// generateKustomization generates the resources each into a separate file,
+// along with a kustomization.yaml listing them as the resources of a kustomize base
+func generateKustomization(gen generators.Interface, opts *options.Options, apiSpec *openapi3.T) (generators.Files, error) {
+	filesGen, ok := gen.(generators.FilesGenerator)
+	if !ok {
+		return nil, fmt.Errorf("%s generator doesn't support --kustomize", gen.Cmd())
+	}
+
+	files, err := filesGen.GenerateFiles(opts, apiSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := files[kustomizationFileName]; ok {
+		return nil, fmt.Errorf("a generated resource would overwrite %s", kustomizationFileName)
+	}
+
+	resources := make([]string, 0, len(files))
+	for fileName := range files {
+		resources = append(resources, fileName)
+	}
+	sort.Strings(resources)
+
+	b, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": kustomizationAPIVersion,
+		"kind":       "Kustomization",
+		"resources":  resources,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", kustomizationFileName, err)
+	}
+
+	files[kustomizationFileName] = string(b)
+
+	return files, nil
+}
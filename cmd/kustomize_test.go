@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+func TestGenerateKustomization(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+  /authors:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := &options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Path: options.PathOptions{
+			Base:  "/api",
+			Split: true,
+		},
+	}
+
+	files, err := generateKustomization(generators.Registry["ingress-nginx"], opts, apiSpec)
+	r.NoError(err)
+
+	var fileNames []string
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	r.ElementsMatch([]string{
+		"kustomization.yaml",
+		"webapp-authors.yaml",
+		"webapp-books.yaml",
+		"webapp-books-id.yaml",
+	}, fileNames)
+
+	r.Equal(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- webapp-authors.yaml
+- webapp-books-id.yaml
+- webapp-books.yaml
+`, files["kustomization.yaml"])
+}
+
+func TestGenerateKustomizationUnsupported(t *testing.T) {
+	r := require.New(t)
+
+	_, err := generateKustomization(generators.Registry["linkerd"], &options.Options{}, &openapi3.T{})
+	r.Error(err)
+}
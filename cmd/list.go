@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+// listPaths returns the included spec paths, one per line, along with the path, path type and name of their route,
+// as computed by the generator without generating the resources
+func listPaths(gen generators.Interface, opts *options.Options, apiSpec *openapi3.T) (string, error) {
+	lister, ok := gen.(generators.PathsLister)
+	if !ok {
+		return "", fmt.Errorf("%s generator doesn't support --list-paths", gen.Cmd())
+	}
+
+	paths, err := lister.ListPaths(opts, apiSpec)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+
+	w := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	for _, path := range paths {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", path.SpecPath, path.Path, path.PathType, path.Name)
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
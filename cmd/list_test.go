@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+func TestListPaths(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /:
+    get: {}
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+  /internal:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := &options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Base:    "/api",
+			Exclude: []string{"/internal"},
+		},
+	}
+
+	res, err := listPaths(generators.Registry["ingress-nginx"], opts, apiSpec)
+	r.NoError(err)
+	r.Equal(`/            /api$               Exact                   webapp-root
+/books       /api/books          Exact                   webapp-books
+/books/{id}  /api/books/([^/]+)  ImplementationSpecific  webapp-books-id
+`, res)
+}
+
+func TestListPathsUnsupported(t *testing.T) {
+	r := require.New(t)
+
+	_, err := listPaths(generators.Registry["linkerd"], &options.Options{}, &openapi3.T{})
+	r.Error(err)
+}
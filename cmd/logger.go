@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// writerLogger is a logr.Logger writing the messages up to its verbosity level as text lines,
+// e.g. DEBUG path decision path=/books decision=included
+type writerLogger struct {
+	out       io.Writer
+	verbosity int
+	level     int
+	name      string
+	values    []interface{}
+}
+
+func newWriterLogger(out io.Writer, verbosity int) logr.Logger {
+	return writerLogger{
+		out:       out,
+		verbosity: verbosity,
+	}
+}
+
+func (l writerLogger) Enabled() bool {
+	return l.level <= l.verbosity
+}
+
+func (l writerLogger) Info(msg string, keysAndValues ...interface{}) {
+	if !l.Enabled() {
+		return
+	}
+
+	prefix := "INFO"
+	if l.level > 0 {
+		prefix = "DEBUG"
+	}
+
+	l.write(prefix, msg, keysAndValues)
+}
+
+func (l writerLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.write("ERROR", msg, append(keysAndValues, "error", err))
+}
+
+func (l writerLogger) V(level int) logr.Logger {
+	l.level += level
+	return l
+}
+
+func (l writerLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	l.values = append(append([]interface{}{}, l.values...), keysAndValues...)
+	return l
+}
+
+func (l writerLogger) WithName(name string) logr.Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	l.name = name
+	return l
+}
+
+func (l writerLogger) write(prefix, msg string, keysAndValues []interface{}) {
+	var builder strings.Builder
+
+	builder.WriteString(prefix)
+	if l.name != "" {
+		builder.WriteString(" " + l.name + ":")
+	}
+	builder.WriteString(" " + msg)
+
+	keysAndValues = append(append([]interface{}{}, l.values...), keysAndValues...)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fmt.Fprintf(&builder, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+
+	fmt.Fprintln(l.out, builder.String())
+}
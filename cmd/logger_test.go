@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterLogger(t *testing.T) {
+	r := require.New(t)
+
+	var out strings.Builder
+	logger := newWriterLogger(&out, 1).WithName("ingress-nginx").WithValues("spec", "petstore")
+
+	logger.Info("generating")
+	logger.V(1).Info("path decision", "path", "/pet", "decision", "included")
+	logger.V(2).Info("not logged")
+	logger.Error(errors.New("boom"), "failed")
+
+	r.Equal(`INFO ingress-nginx: generating spec=petstore
+DEBUG ingress-nginx: path decision spec=petstore path=/pet decision=included
+ERROR ingress-nginx: failed spec=petstore error=boom
+`, out.String())
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+// managedAnnotationPrefix is the prefix of the annotations managed by kusk.
+// When merging a generated Ingress into an existing one, the existing annotations with this prefix
+// are replaced by the generated ones, so that the annotations kusk no longer generates are removed.
+const managedAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+
+// mergeResources merges each generated Ingress into the Ingress of the same name found in the given manifest, if any.
+// Kusk manages the spec, the labels it generates and the annotations prefixed by managedAnnotationPrefix,
+// all the other fields of the existing Ingress, e.g. its status or other annotations, are preserved.
+// Other resources are returned as generated.
+func mergeResources(resources []runtime.Object, manifest []byte) ([]runtime.Object, error) {
+	existing, err := decodeIngresses(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make([]runtime.Object, 0, len(resources))
+	for _, resource := range resources {
+		generated, ok := resource.(*networkingv1.Ingress)
+		if !ok {
+			merged = append(merged, resource)
+			continue
+		}
+
+		existingIngress, ok := existing[generated.Name]
+		if !ok || (generated.Namespace != "" && existingIngress.Namespace != "" && generated.Namespace != existingIngress.Namespace) {
+			merged = append(merged, resource)
+			continue
+		}
+
+		merged = append(merged, mergeIngress(existingIngress, generated))
+	}
+
+	return merged, nil
+}
+
+func mergeIngress(existing, generated *networkingv1.Ingress) *networkingv1.Ingress {
+	merged := existing.DeepCopy()
+	merged.TypeMeta = generated.TypeMeta
+	merged.Spec = *generated.Spec.DeepCopy()
+
+	if generated.Namespace != "" {
+		merged.Namespace = generated.Namespace
+	}
+
+	annotations := map[string]string{}
+	for key, value := range existing.Annotations {
+		if !strings.HasPrefix(key, managedAnnotationPrefix) {
+			annotations[key] = value
+		}
+	}
+
+	for key, value := range generated.Annotations {
+		annotations[key] = value
+	}
+
+	labels := map[string]string{}
+	for key, value := range existing.Labels {
+		labels[key] = value
+	}
+
+	for key, value := range generated.Labels {
+		labels[key] = value
+	}
+
+	merged.Annotations = annotations
+	merged.Labels = labels
+
+	return merged
+}
+
+// decodeIngresses returns the networking.k8s.io/v1 Ingresses of the given YAML manifest by name,
+// skipping the other resources
+func decodeIngresses(manifest []byte) (map[string]*networkingv1.Ingress, error) {
+	ingresses := map[string]*networkingv1.Ingress{}
+
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return ingresses, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+
+		var typeMeta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+
+		if typeMeta.APIVersion != "networking.k8s.io/v1" || typeMeta.Kind != "Ingress" {
+			continue
+		}
+
+		var ingress networkingv1.Ingress
+		if err := yaml.Unmarshal(doc, &ingress); err != nil {
+			return nil, fmt.Errorf("failed to decode Ingress: %w", err)
+		}
+
+		ingresses[ingress.Name] = &ingress
+	}
+}
+
+// generateMerged generates the resources and merges them into the ones of the given manifest file
+func generateMerged(gen generators.Interface, opts *options.Options, apiSpec *openapi3.T, manifestPath string) (string, error) {
+	resourcesGen, ok := gen.(generators.ResourcesGenerator)
+	if !ok {
+		return "", fmt.Errorf("%s generator doesn't support --merge-from", gen.Cmd())
+	}
+
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+
+	resources, err := resourcesGen.GenerateResources(opts, apiSpec)
+	if err != nil {
+		return "", err
+	}
+
+	merged, err := mergeResources(resources, manifest)
+	if err != nil {
+		return "", fmt.Errorf("failed to merge into %s: %w", manifestPath, err)
+	}
+
+	return generators.MarshalResources(merged)
+}
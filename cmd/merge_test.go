@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMergeResources(t *testing.T) {
+	r := require.New(t)
+
+	manifest := `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: webapp
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: webapp-ingress
+  namespace: default
+  annotations:
+    cert-manager.io/cluster-issuer: letsencrypt
+    nginx.ingress.kubernetes.io/rewrite-target: /$2
+  labels:
+    team: books
+spec:
+  rules:
+  - http:
+      paths:
+      - path: /old
+        pathType: Prefix
+        backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+status:
+  loadBalancer:
+    ingress:
+    - ip: 10.0.0.1
+`
+
+	generated := newTestIngress("default", "http", map[string]string{
+		"nginx.ingress.kubernetes.io/ssl-redirect": "true",
+	})
+	generated.Labels = map[string]string{"app.kubernetes.io/managed-by": "kusk"}
+
+	res, err := mergeResources([]runtime.Object{generated}, []byte(manifest))
+	r.NoError(err)
+	r.Len(res, 1)
+
+	merged, ok := res[0].(*networkingv1.Ingress)
+	r.True(ok)
+
+	r.Equal(map[string]string{
+		"cert-manager.io/cluster-issuer":           "letsencrypt",
+		"nginx.ingress.kubernetes.io/ssl-redirect": "true",
+	}, merged.Annotations)
+	r.Equal(map[string]string{
+		"app.kubernetes.io/managed-by": "kusk",
+		"team":                         "books",
+	}, merged.Labels)
+	r.Equal(generated.Spec, merged.Spec)
+	r.Equal("10.0.0.1", merged.Status.LoadBalancer.Ingress[0].IP)
+}
+
+func TestMergeResourcesNotFound(t *testing.T) {
+	r := require.New(t)
+
+	generated := newTestIngress("default", "http", nil)
+
+	res, err := mergeResources([]runtime.Object{generated}, []byte(`---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: webapp-ingress
+  namespace: books
+`))
+	r.NoError(err)
+	r.Equal([]runtime.Object{generated}, res)
+}
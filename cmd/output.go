@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeOutput writes the generated content to the file at the given path, creating parent directories if needed.
+// An existing file is only overwritten when force is set.
+func writeOutput(path, content string, force bool) error {
+	_, err := os.Stat(path)
+	if err == nil && !force {
+		return fmt.Errorf("output file %s already exists, use --force to overwrite it", path)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check output file %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write output file %s: %w", path, err)
+	}
+
+	return nil
+}
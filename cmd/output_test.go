@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOutput(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "nested", "dir", "ingress.yaml")
+	content := "---\nkind: Ingress\n---\nkind: Ingress\n"
+
+	r.NoError(writeOutput(path, content, false))
+
+	b, err := os.ReadFile(path)
+	r.NoError(err)
+	r.Equal(content, string(b))
+
+	// existing file is not overwritten without force
+	r.Error(writeOutput(path, "---\nkind: Service\n", false))
+
+	b, err = os.ReadFile(path)
+	r.NoError(err)
+	r.Equal(content, string(b))
+
+	// existing file is overwritten with force
+	r.NoError(writeOutput(path, "---\nkind: Service\n", true))
+
+	b, err = os.ReadFile(path)
+	r.NoError(err)
+	r.Equal("---\nkind: Service\n", string(b))
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+// validate runs the same checks as generation would, without producing any output,
+// and returns all the problems found
+func validate(gen generators.Interface, opts *options.Options, apiSpec *openapi3.T) []error {
+	// the options are checked on a copy, as the generators derive some of the defaults themselves,
+	// e.g. path.base from the spec servers, which filling the defaults first would override
+	checkedOpts := *opts
+	if err := checkedOpts.FillDefaultsAndValidate(); err != nil {
+		// the rest of the checks rely on valid options
+		return []error{fmt.Errorf("invalid options: %w", err)}
+	}
+
+	var problems []error
+
+	if !checkedOpts.AllowEmpty {
+		if len(apiSpec.Paths) == 0 && !checkedOpts.Path.Default {
+			problems = append(problems, fmt.Errorf("spec has no paths"))
+		} else if allPathsDisabled(&checkedOpts, apiSpec) {
+			problems = append(problems, fmt.Errorf("all paths are disabled"))
+		}
+
+		// there is nothing else to check, and the generators failing on empty specs would report the same problem
+		if len(problems) > 0 {
+			return problems
+		}
+	}
+
+	// generate resources and discard them to catch generator specific problems, e.g. duplicate resource names
+	var err error
+	if filesGen, ok := gen.(generators.FilesGenerator); ok {
+		_, err = filesGen.GenerateFiles(opts, apiSpec)
+	} else {
+		_, err = gen.Generate(opts, apiSpec)
+	}
+
+	if err != nil {
+		problems = append(problems, err)
+	}
+
+	return problems
+}
+
+func allPathsDisabled(opts *options.Options, apiSpec *openapi3.T) bool {
+	for path := range apiSpec.Paths {
+		if !opts.IsPathDisabled(path) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validationReport formats the problems into a readable report
+func validationReport(problems []error) string {
+	var builder strings.Builder
+
+	builder.WriteString("validation failed:\n")
+	for _, problem := range problems {
+		fmt.Fprintf(&builder, "  - %s\n", problem)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  options.Options
+		spec     string
+		problems []string
+	}{
+		{
+			name: "valid",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+			},
+			spec: `openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`,
+		},
+		{
+			name: "empty service name",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+				},
+			},
+			spec: `openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`,
+			problems: []string{"invalid options: 1: (name: service.name is required.)."},
+		},
+		{
+			name: "no paths",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+			},
+			spec: `openapi: 3.0.1
+paths: {}
+`,
+			problems: []string{"spec has no paths"},
+		},
+		{
+			name: "all paths disabled",
+			options: options.Options{
+				Disabled: true,
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+			},
+			spec: `openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`,
+			problems: []string{"all paths are disabled"},
+		},
+		{
+			name: "duplicate resource names",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+			},
+			spec: `openapi: 3.0.1
+paths:
+  /books/id:
+    get: {}
+  /books/{id}:
+    get: {}
+`,
+			problems: []string{"/books/id and /books/{id} would both generate an Ingress named webapp-books-id, use ingress.dedupe_names to disambiguate them"},
+		},
+	}
+
+	gen := generators.Registry["ingress-nginx"]
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err)
+
+			var problems []string
+			for _, problem := range validate(gen, &testCase.options, apiSpec) {
+				problems = append(problems, problem.Error())
+			}
+
+			r.Equal(testCase.problems, problems)
+		})
+	}
+}
+
+// recordingGenerator is an ingress-nginx generator recording its last output
+type recordingGenerator struct {
+	generators.Interface
+	output string
+}
+
+func (g *recordingGenerator) Generate(opts *options.Options, apiSpec *openapi3.T) (string, error) {
+	output, err := g.Interface.Generate(opts, apiSpec)
+	g.output = output
+
+	return output, err
+}
+
+func TestValidateServerBasePath(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+	}
+
+	gen := &recordingGenerator{Interface: generators.Registry["ingress-nginx"]}
+
+	r.Empty(validate(gen, &opts, apiSpec))
+	// the routes are validated under the base path derived from the servers, as generated
+	r.Contains(gen.output, "path: /v1/books\n")
+}
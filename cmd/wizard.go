@@ -3,8 +3,8 @@ package cmd
 import (
 	"log"
 	"os"
+	"time"
 
-	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
@@ -14,7 +14,10 @@ import (
 )
 
 func init() {
-	var apiSpecPath string
+	var (
+		apiSpecPath  string
+		fetchTimeout time.Duration
+	)
 
 	wizardCmd := &cobra.Command{
 		Use:   "wizard",
@@ -25,7 +28,7 @@ func init() {
 			}
 
 			// parse OpenAPI spec
-			apiSpec, err := spec.NewParser(openapi3.NewLoader()).Parse(apiSpecPath)
+			apiSpec, err := spec.NewParser(newSpecLoader(fetchTimeout)).Parse(apiSpecPath)
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -40,9 +43,16 @@ func init() {
 		"in",
 		"i",
 		"",
-		"file path to api spec file to generate mappings from. e.g. --in apispec.yaml",
+		"file path or http(s) URL to api spec file to generate mappings from. e.g. --in apispec.yaml",
 	)
 	wizardCmd.MarkFlagRequired("in")
 
+	wizardCmd.Flags().DurationVar(
+		&fetchTimeout,
+		"timeout",
+		defaultFetchTimeout,
+		"timeout for fetching the api spec from a URL",
+	)
+
 	rootCmd.AddCommand(wizardCmd)
 }
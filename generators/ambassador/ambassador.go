@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
 )
 
 var (
@@ -95,13 +96,17 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 
 	serviceURL := getServiceURL(opts)
 
+	// mappings and rate limits are named after the prefixed service name
+	resourceName := opts.NamePrefix + opts.Service.Name
+
 	if shouldSplit(opts, spec) {
 		// generate a mapping for each operation
 		basePath := strings.TrimSuffix(opts.Path.Base, "/")
 
 		host := opts.Host
 
-		for path, pathItem := range spec.Paths {
+		for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+			pathItem := spec.Paths[path]
 			pathSubOptions := opts.PathSubOptions[path]
 
 			if pathSubOptions.Host != "" && pathSubOptions.Host != host {
@@ -119,7 +124,7 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 				}
 
 				mappingPath, regex := generateMappingPath(path, operation)
-				mappingName := generateMappingName(opts.Service.Name, method, path, operation)
+				mappingName := generateMappingName(resourceName, method, path, operation)
 
 				var pathRewrite string
 				if opts.Path.Rewrite != "" {
@@ -180,7 +185,7 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 							}
 						} else {
 							rateLimits[rateLimitOpts.Group] = &rateLimitTemplateData{
-								Name:        opts.Service.Name + "-" + rateLimitOpts.Group,
+								Name:        resourceName + "-" + rateLimitOpts.Group,
 								Operation:   mappingName,
 								Rate:        rps,
 								BurstFactor: burstFactor,
@@ -190,7 +195,7 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 					} else {
 						// rate limit on this operation does not use grouping
 						rateLimits[mappingName] = &rateLimitTemplateData{
-							Name:        opts.Service.Name + "-" + mappingName,
+							Name:        resourceName + "-" + mappingName,
 							Operation:   mappingName,
 							Rate:        rps,
 							BurstFactor: burstFactor,
@@ -215,7 +220,7 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 		}
 	} else if !opts.Disabled {
 		op := mappingTemplateData{
-			MappingName:      opts.Service.Name,
+			MappingName:      resourceName,
 			MappingNamespace: opts.Namespace,
 			ServiceURL:       serviceURL,
 			BasePath:         opts.Path.Base,
@@ -259,7 +264,7 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 
 			rateLimits["default"] = &rateLimitTemplateData{
 				Name:        "default",
-				Operation:   opts.Service.Name,
+				Operation:   resourceName,
 				Rate:        rps,
 				BurstFactor: burstFactor,
 				Group:       opts.RateLimits.Group,
@@ -377,7 +382,8 @@ func shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 		return true
 	}
 
-	for path, pathItem := range spec.Paths {
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
 		for method := range pathItem.Operations() {
 			if opts.IsOperationDisabled(path, method) {
 				return true
@@ -388,7 +394,8 @@ func shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 		}
 	}
 
-	for path, pathItem := range spec.Paths {
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
 		if pathSubOptions, ok := opts.PathSubOptions[path]; ok {
 			// a path has non-zero, different from global scope CORS options
 			if !reflect.DeepEqual(options.CORSOptions{}, pathSubOptions.CORS) &&
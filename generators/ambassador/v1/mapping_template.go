@@ -6,7 +6,9 @@ apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: {{.MappingName}}
+  {{- if .MappingNamespace}}
   namespace: {{.MappingNamespace}}
+  {{- end}}
 spec:
   prefix: "{{.BasePath}}{{.Path}}" 
 
@@ -6,7 +6,9 @@ apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: {{.MappingName}}
+  {{- if .MappingNamespace}}
   namespace: {{.MappingNamespace}}
+  {{- end}}
 spec:
   prefix: "{{.BasePath}}{{.Path}}"
 
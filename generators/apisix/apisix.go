@@ -0,0 +1,216 @@
+package apisix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
+)
+
+var (
+	openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+
+	invalidRuleNameCharsRegex = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func init() {
+	generators.Registry["apisix"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "apisix"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("apisix", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"a Host to listen on",
+	)
+
+	fs.String(
+		"service.port_name",
+		"",
+		"reference the target Service port by name instead of service.port",
+	)
+
+	fs.StringArray(
+		"apisix.plugins",
+		[]string{},
+		"a plugin to enable on all routes as name or name={json config}, can be repeated",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Apache APISIX ApisixRoute resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return "", fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	// ApisixRoute backends can only reference Services in the namespace of the route
+	if opts.Namespace != "" && opts.Namespace != opts.Service.Namespace {
+		return "", fmt.Errorf("ApisixRoute must be in the namespace of the Service, namespace %s differs from service.namespace %s", opts.Namespace, opts.Service.Namespace)
+	}
+
+	plugins, err := g.generatePlugins(opts)
+	if err != nil {
+		return "", err
+	}
+
+	rules := g.generateRules(opts, spec, plugins)
+	if len(rules) == 0 {
+		return "", nil
+	}
+
+	resource := g.newApisixRoute(opts, rules)
+
+	b, err := generators.Marshal(resource)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal resource: %+v: %s", resource, err.Error())
+	}
+
+	return "---\n" + string(b), nil
+}
+
+// generateRules translates each enabled spec path into an ApisixRoute http rule
+func (g *Generator) generateRules(opts *options.Options, spec *openapi3.T, plugins []apisixRoutePlugin) []apisixRouteHTTP {
+	backend := g.backend(opts)
+	hosts := g.hosts(opts)
+
+	// rule names have to be unique within the route
+	names := map[string]bool{}
+
+	var rules []apisixRouteHTTP
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathDisabled(path) {
+			continue
+		}
+
+		name := ruleName(path)
+		for n := 2; names[name]; n++ {
+			name = fmt.Sprintf("%s-%d", ruleName(path), n)
+		}
+		names[name] = true
+
+		rules = append(rules, apisixRouteHTTP{
+			Name: name,
+			Match: apisixRouteHTTPMatch{
+				Paths: []string{g.generatePath(opts.Path.Base, path)},
+				Hosts: hosts,
+			},
+			Backends: []apisixRouteHTTPBackend{backend},
+			Plugins:  plugins,
+		})
+	}
+
+	return rules
+}
+
+// generatePath returns the path to match for the given spec path. APISIX only supports a * wildcard
+// at the end of a path, so paths containing variables are matched by the prefix before the first one.
+func (g *Generator) generatePath(base, path string) string {
+	uri := base
+	if path != "/" {
+		uri = strings.TrimSuffix(base, "/") + path
+	}
+
+	if loc := openApiPathVariableRegex.FindStringIndex(uri); loc != nil {
+		return uri[:loc[0]] + "*"
+	}
+
+	return uri
+}
+
+// ruleName turns the path into a valid rule name, e.g. /pets/{petId} into pets-petid
+func ruleName(path string) string {
+	name := invalidRuleNameCharsRegex.ReplaceAllString(strings.ToLower(path), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "root"
+	}
+
+	return name
+}
+
+// backend returns the reference to the target Service port, by name if service.port_name is set
+func (g *Generator) backend(opts *options.Options) apisixRouteHTTPBackend {
+	port := intstr.FromInt(int(opts.Service.Port))
+	if opts.Service.PortName != "" {
+		port = intstr.FromString(opts.Service.PortName)
+	}
+
+	return apisixRouteHTTPBackend{
+		ServiceName: opts.Service.Name,
+		ServicePort: port,
+	}
+}
+
+// hosts returns the host to match, no hosts matching all of them
+func (g *Generator) hosts(opts *options.Options) []string {
+	if opts.Host != "" && opts.Host != "*" {
+		return []string{opts.Host}
+	}
+
+	return nil
+}
+
+func (g *Generator) generatePlugins(opts *options.Options) ([]apisixRoutePlugin, error) {
+	parsed, err := opts.APISIX.ParsedPlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []apisixRoutePlugin
+	for _, plugin := range parsed {
+		plugins = append(plugins, apisixRoutePlugin{
+			Name:   plugin.Name,
+			Enable: true,
+			Config: plugin.Config,
+		})
+	}
+
+	return plugins, nil
+}
+
+func (g *Generator) newApisixRoute(opts *options.Options, rules []apisixRouteHTTP) apisixRoute {
+	return apisixRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apisixAPIVersion,
+			Kind:       apisixRouteKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.NamePrefix + opts.Service.Name,
+			Namespace: opts.Service.Namespace,
+		},
+		Spec: apisixRouteSpec{
+			HTTP: rules,
+		},
+	}
+}
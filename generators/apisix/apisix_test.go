@@ -0,0 +1,271 @@
+package apisix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestAPISIX(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			res, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestAPISIXInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts options.Options
+	}{
+		{
+			name: "invalid plugin name",
+			opts: options.Options{
+				APISIX: options.APISIXOptions{
+					Plugins: []string{"Proxy Rewrite"},
+				},
+			},
+		},
+		{
+			name: "plugin config not a JSON object",
+			opts: options.Options{
+				APISIX: options.APISIXOptions{
+					Plugins: []string{"cors=[]"},
+				},
+			},
+		},
+		{
+			name: "namespace differs from service.namespace",
+			opts: options.Options{
+				Namespace: "apisix",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := testCase.opts
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+		})
+	}
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "simple routes",
+		options: options.Options{
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets:
+    get: {}
+
+  /pets/{petId}/photos:
+    get: {}
+`,
+		res: `---
+apiVersion: apisix.apache.org/v2
+kind: ApisixRoute
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  http:
+  - backends:
+    - serviceName: petstore
+      servicePort: 80
+    match:
+      paths:
+      - /
+    name: root
+  - backends:
+    - serviceName: petstore
+      servicePort: 80
+    match:
+      paths:
+      - /pets
+    name: pets
+  - backends:
+    - serviceName: petstore
+      servicePort: 80
+    match:
+      paths:
+      - /pets/*
+    name: pets-petid-photos
+`,
+	},
+	{
+		name: "base path, host and port name",
+		options: options.Options{
+			Namespace: "petstore",
+			Host:      "example.org",
+			Path: options.PathOptions{
+				Base: "/api",
+			},
+			Service: options.ServiceOptions{
+				Namespace: "petstore",
+				Name:      "petstore",
+				PortName:  "http",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: apisix.apache.org/v2
+kind: ApisixRoute
+metadata:
+  name: petstore
+  namespace: petstore
+spec:
+  http:
+  - backends:
+    - serviceName: petstore
+      servicePort: http
+    match:
+      hosts:
+      - example.org
+      paths:
+      - /api
+    name: root
+  - backends:
+    - serviceName: petstore
+      servicePort: http
+    match:
+      hosts:
+      - example.org
+      paths:
+      - /api/pets/*
+    name: pets-petid
+`,
+	},
+	{
+		name: "disabled path",
+		options: options.Options{
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/internal": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /internal:
+    get: {}
+
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: apisix.apache.org/v2
+kind: ApisixRoute
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  http:
+  - backends:
+    - serviceName: petstore
+      servicePort: 80
+    match:
+      paths:
+      - /pets
+    name: pets
+`,
+	},
+	{
+		name: "plugins",
+		options: options.Options{
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			APISIX: options.APISIXOptions{
+				Plugins: []string{
+					`cors={"allow_origins":"http://foo.example","allow_methods":"GET,POST"}`,
+					"request-id",
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: apisix.apache.org/v2
+kind: ApisixRoute
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  http:
+  - backends:
+    - serviceName: petstore
+      servicePort: 80
+    match:
+      paths:
+      - /pets
+    name: pets
+    plugins:
+    - config:
+        allow_methods: GET,POST
+        allow_origins: http://foo.example
+      enable: true
+      name: cors
+    - enable: true
+      name: request-id
+`,
+	},
+}
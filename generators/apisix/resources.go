@@ -0,0 +1,48 @@
+package apisix
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	apisixAPIVersion = "apisix.apache.org/v2"
+
+	apisixRouteKind = "ApisixRoute"
+)
+
+// apisixRoute is a subset of the Apache APISIX ApisixRoute resource
+// See https://apisix.apache.org/docs/ingress-controller/references/apisix_route_v2/
+type apisixRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec apisixRouteSpec `json:"spec"`
+}
+
+type apisixRouteSpec struct {
+	HTTP []apisixRouteHTTP `json:"http"`
+}
+
+type apisixRouteHTTP struct {
+	Name     string                   `json:"name"`
+	Match    apisixRouteHTTPMatch     `json:"match"`
+	Backends []apisixRouteHTTPBackend `json:"backends"`
+	Plugins  []apisixRoutePlugin      `json:"plugins,omitempty"`
+}
+
+type apisixRouteHTTPMatch struct {
+	Paths []string `json:"paths"`
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+type apisixRouteHTTPBackend struct {
+	ServiceName string             `json:"serviceName"`
+	ServicePort intstr.IntOrString `json:"servicePort"`
+}
+
+type apisixRoutePlugin struct {
+	Name   string                 `json:"name"`
+	Enable bool                   `json:"enable"`
+	Config map[string]interface{} `json:"config,omitempty"`
+}
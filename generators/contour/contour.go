@@ -0,0 +1,236 @@
+package contour
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
+)
+
+var openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+
+func init() {
+	generators.Registry["contour"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "contour"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("contour", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"a Host to listen on",
+	)
+
+	fs.Uint32(
+		"timeouts.request_timeout",
+		0,
+		"total request timeout (seconds)",
+	)
+
+	fs.Uint32(
+		"timeouts.idle_timeout",
+		0,
+		"idle connection timeout (seconds)",
+	)
+
+	fs.String(
+		"contour.fqdn",
+		"",
+		"the fully qualified domain name of the virtual host, overrides host",
+	)
+
+	fs.String(
+		"contour.tls_secret",
+		"",
+		"a name of the Secret containing TLS certificate for the virtual host, in the form of name or namespace/name",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Contour HTTPProxy resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return "", fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	fqdn := opts.Contour.FQDN
+	if fqdn == "" {
+		fqdn = opts.Host
+	}
+
+	if fqdn == "" {
+		return "", fmt.Errorf("failed to validate options: host or contour.fqdn is required")
+	}
+
+	if opts.Namespace == "" && strings.Contains(opts.Contour.TLSSecret, "/") {
+		return "", fmt.Errorf("failed to validate options: namespace is required to delegate contour.tls_secret from another namespace")
+	}
+
+	routes := g.generateRoutes(opts, spec)
+	if len(routes) == 0 {
+		return "", nil
+	}
+
+	resources := []interface{}{g.newHTTPProxy(opts, fqdn, routes)}
+
+	if delegation, ok := g.newTLSCertificateDelegation(opts); ok {
+		resources = append(resources, delegation)
+	}
+
+	var builder strings.Builder
+
+	for _, resource := range resources {
+		builder.WriteString("---\n") // indicate start of YAML resource
+		b, err := generators.Marshal(resource)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal resource: %+v: %s", resource, err.Error())
+		}
+		builder.WriteString(string(b))
+	}
+
+	return builder.String(), nil
+}
+
+// generateRoutes translates each enabled spec path into an HTTPProxy route
+func (g *Generator) generateRoutes(opts *options.Options, spec *openapi3.T) []route {
+	var timeouts *timeoutPolicy
+	if opts.Timeouts.RequestTimeout > 0 || opts.Timeouts.IdleTimeout > 0 {
+		timeouts = &timeoutPolicy{}
+
+		if requestTimeout := opts.Timeouts.RequestTimeout; requestTimeout > 0 {
+			timeouts.Response = fmt.Sprintf("%ds", requestTimeout)
+		}
+
+		if idleTimeout := opts.Timeouts.IdleTimeout; idleTimeout > 0 {
+			timeouts.Idle = fmt.Sprintf("%ds", idleTimeout)
+		}
+	}
+
+	var routes []route
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathDisabled(path) {
+			continue
+		}
+
+		routes = append(routes, route{
+			Conditions: []matchCondition{g.generateCondition(opts.Path.Base, path)},
+			Services: []service{
+				{
+					Name: opts.Service.Name,
+					Port: opts.Service.Port,
+				},
+			},
+			TimeoutPolicy: timeouts,
+		})
+	}
+
+	return routes
+}
+
+func (g *Generator) generateCondition(base, path string) matchCondition {
+	fullPath := base
+	if path != "/" {
+		fullPath = strings.TrimSuffix(base, "/") + path
+	}
+
+	if openApiPathVariableRegex.MatchString(path) {
+		// quote the static parts of the path so that they are matched literally
+		parts := openApiPathVariableRegex.Split(fullPath, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+
+		return matchCondition{
+			Regex: strings.Join(parts, `[^/]+`),
+		}
+	}
+
+	return matchCondition{
+		Prefix: fullPath,
+	}
+}
+
+func (g *Generator) newHTTPProxy(opts *options.Options, fqdn string, routes []route) httpProxy {
+	vhost := &virtualHost{
+		FQDN: fqdn,
+	}
+
+	if opts.Contour.TLSSecret != "" {
+		vhost.TLS = &tls{
+			SecretName: opts.Contour.TLSSecret,
+		}
+	}
+
+	return httpProxy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: contourAPIVersion,
+			Kind:       httpProxyKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.NamePrefix + opts.Service.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: httpProxySpec{
+			VirtualHost: vhost,
+			Routes:      routes,
+		},
+	}
+}
+
+// newTLSCertificateDelegation delegates the TLS secret to the HTTPProxy namespace
+// if the secret is referenced in another namespace
+func (g *Generator) newTLSCertificateDelegation(opts *options.Options) (tlsCertificateDelegation, bool) {
+	parts := strings.SplitN(opts.Contour.TLSSecret, "/", 2)
+	if len(parts) != 2 || parts[0] == opts.Namespace {
+		return tlsCertificateDelegation{}, false
+	}
+
+	secretNamespace, secretName := parts[0], parts[1]
+
+	return tlsCertificateDelegation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: contourAPIVersion,
+			Kind:       tlsCertificateDelegationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s%s-%s", opts.NamePrefix, opts.Service.Name, secretName),
+			Namespace: secretNamespace,
+		},
+		Spec: tlsCertificateDelegationSpec{
+			Delegations: []certificateDelegation{
+				{
+					SecretName:       secretName,
+					TargetNamespaces: []string{opts.Namespace},
+				},
+			},
+		},
+	}, true
+}
@@ -0,0 +1,263 @@
+package contour
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestContour(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			res, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestContourFQDNRequired(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "petstore",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestContourDelegationRequiresNamespace(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Host: "example.org",
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "petstore",
+		},
+		Contour: options.ContourOptions{
+			TLSSecret: "certs/petstore-tls",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "simple routes",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - prefix: /
+    services:
+    - name: petstore
+      port: 80
+  - conditions:
+    - regex: /pets/[^/]+
+    services:
+    - name: petstore
+      port: 80
+  virtualhost:
+    fqdn: example.org
+`,
+	},
+	{
+		name: "fqdn override, base path, tls secret in the same namespace and timeouts",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+				Port:      8080,
+			},
+			Path: options.PathOptions{
+				Base: "/api",
+			},
+			Contour: options.ContourOptions{
+				FQDN:      "petstore.example.org",
+				TLSSecret: "petstore-tls",
+			},
+			Timeouts: options.TimeoutOptions{
+				RequestTimeout: 30,
+				IdleTimeout:    60,
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - prefix: /api
+    services:
+    - name: petstore
+      port: 8080
+    timeoutPolicy:
+      idle: 60s
+      response: 30s
+  - conditions:
+    - prefix: /api/pets
+    services:
+    - name: petstore
+      port: 8080
+    timeoutPolicy:
+      idle: 60s
+      response: 30s
+  virtualhost:
+    fqdn: petstore.example.org
+    tls:
+      secretName: petstore-tls
+`,
+	},
+	{
+		name: "tls secret delegated from another namespace",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Contour: options.ContourOptions{
+				TLSSecret: "certs/wildcard",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - prefix: /pets
+    services:
+    - name: petstore
+      port: 80
+  virtualhost:
+    fqdn: example.org
+    tls:
+      secretName: certs/wildcard
+---
+apiVersion: projectcontour.io/v1
+kind: TLSCertificateDelegation
+metadata:
+  name: petstore-wildcard
+  namespace: certs
+spec:
+  delegations:
+  - secretName: wildcard
+    targetNamespaces:
+    - default
+`,
+	},
+	{
+		name: "disabled path is excluded",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/internal": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+
+  /internal:
+    get: {}
+`,
+		res: `---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - prefix: /pets
+    services:
+    - name: petstore
+      port: 80
+  virtualhost:
+    fqdn: example.org
+`,
+	},
+}
@@ -0,0 +1,74 @@
+package contour
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	contourAPIVersion = "projectcontour.io/v1"
+
+	httpProxyKind                = "HTTPProxy"
+	tlsCertificateDelegationKind = "TLSCertificateDelegation"
+)
+
+// httpProxy is a subset of the Contour HTTPProxy resource
+// See https://projectcontour.io/docs/main/config/api/#projectcontour.io/v1.HTTPProxy
+type httpProxy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec httpProxySpec `json:"spec"`
+}
+
+type httpProxySpec struct {
+	VirtualHost *virtualHost `json:"virtualhost,omitempty"`
+	Routes      []route      `json:"routes,omitempty"`
+}
+
+type virtualHost struct {
+	FQDN string `json:"fqdn"`
+	TLS  *tls   `json:"tls,omitempty"`
+}
+
+type tls struct {
+	SecretName string `json:"secretName"`
+}
+
+type route struct {
+	Conditions    []matchCondition `json:"conditions,omitempty"`
+	Services      []service        `json:"services"`
+	TimeoutPolicy *timeoutPolicy   `json:"timeoutPolicy,omitempty"`
+}
+
+type matchCondition struct {
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+type service struct {
+	Name string `json:"name"`
+	Port int32  `json:"port"`
+}
+
+type timeoutPolicy struct {
+	Response string `json:"response,omitempty"`
+	Idle     string `json:"idle,omitempty"`
+}
+
+// tlsCertificateDelegation is a subset of the Contour TLSCertificateDelegation resource
+// See https://projectcontour.io/docs/main/config/api/#projectcontour.io/v1.TLSCertificateDelegation
+type tlsCertificateDelegation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec tlsCertificateDelegationSpec `json:"spec"`
+}
+
+type tlsCertificateDelegationSpec struct {
+	Delegations []certificateDelegation `json:"delegations"`
+}
+
+type certificateDelegation struct {
+	SecretName       string   `json:"secretName"`
+	TargetNamespaces []string `json:"targetNamespaces"`
+}
@@ -0,0 +1,114 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const defaultIndent = 2
+
+// FormatOptions control the serialization of the generated YAML output
+type FormatOptions struct {
+	// Indent is the number of spaces to indent the nested blocks with, sequences included.
+	// When not set, sequences are kept at the indentation of their key.
+	Indent int
+
+	// OmitEmpty leaves out the empty collections, e.g. resources: {}, instead of rendering them as {} or [].
+	OmitEmpty bool
+}
+
+// IsDefault returns whether the options keep the output as generated
+func (o FormatOptions) IsDefault() bool {
+	return o == FormatOptions{}
+}
+
+// Format re-serializes each YAML document of the multi-document output of a generator with the given options.
+// The output is returned as is with the default options.
+func Format(output string, opts FormatOptions) (string, error) {
+	if opts.IsDefault() {
+		return output, nil
+	}
+
+	if opts.Indent < 0 {
+		return "", fmt.Errorf("indent must be positive, got %d", opts.Indent)
+	}
+
+	indent := opts.Indent
+	if indent == 0 {
+		indent = defaultIndent
+	}
+
+	var builder strings.Builder
+
+	for i, document := range SplitDocuments(output) {
+		var node yamlv3.Node
+		if err := yamlv3.Unmarshal([]byte(document), &node); err != nil {
+			return "", fmt.Errorf("failed to parse resource %d: %w", i, err)
+		}
+
+		if opts.OmitEmpty {
+			omitEmptyCollections(&node)
+		}
+
+		var buf bytes.Buffer
+
+		encoder := yamlv3.NewEncoder(&buf)
+		encoder.SetIndent(indent)
+		if err := encoder.Encode(&node); err != nil {
+			return "", fmt.Errorf("failed to format resource %d: %w", i, err)
+		}
+
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("failed to format resource %d: %w", i, err)
+		}
+
+		builder.WriteString(DocumentSeparator)
+		builder.Write(buf.Bytes())
+	}
+
+	return builder.String(), nil
+}
+
+// Format re-serializes the YAML documents of each file with the given options
+func (f Files) Format(opts FormatOptions) error {
+	for fileName, content := range f {
+		formatted, err := Format(content, opts)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", fileName, err)
+		}
+
+		f[fileName] = formatted
+	}
+
+	return nil
+}
+
+// omitEmptyCollections removes the mapping entries whose value is an empty mapping or sequence,
+// including the ones left empty by the removal of their own entries
+func omitEmptyCollections(node *yamlv3.Node) {
+	for _, child := range node.Content {
+		omitEmptyCollections(child)
+	}
+
+	if node.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	content := make([]*yamlv3.Node, 0, len(node.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if isEmptyCollection(node.Content[i+1]) {
+			continue
+		}
+
+		content = append(content, node.Content[i], node.Content[i+1])
+	}
+
+	node.Content = content
+}
+
+func isEmptyCollection(node *yamlv3.Node) bool {
+	return (node.Kind == yamlv3.MappingNode || node.Kind == yamlv3.SequenceNode) && len(node.Content) == 0
+}
@@ -0,0 +1,113 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	output := `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+  name: webapp-ingress
+spec:
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+        path: /
+  tls: []
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels: {}
+  name: webapp
+`
+
+	testCases := []struct {
+		name string
+		opts FormatOptions
+		res  string
+	}{
+		{
+			name: "default",
+			res:  output,
+		},
+		{
+			name: "indent",
+			opts: FormatOptions{Indent: 4},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+    annotations:
+        nginx.ingress.kubernetes.io/ssl-redirect: "false"
+    name: webapp-ingress
+spec:
+    rules:
+        - http:
+            paths:
+                - backend:
+                    service:
+                        name: webapp
+                  path: /
+    tls: []
+---
+apiVersion: v1
+kind: Service
+metadata:
+    labels: {}
+    name: webapp
+`,
+		},
+		{
+			name: "omit empty",
+			opts: FormatOptions{OmitEmpty: true},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+  name: webapp-ingress
+spec:
+  rules:
+    - http:
+        paths:
+          - backend:
+              service:
+                name: webapp
+            path: /
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: webapp
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			res, err := Format(output, testCase.opts)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestFormatInvalidIndent(t *testing.T) {
+	_, err := Format("---\nkind: Ingress\n", FormatOptions{Indent: -1})
+	require.Error(t, err)
+}
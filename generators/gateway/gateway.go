@@ -0,0 +1,224 @@
+package gateway
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
+)
+
+var openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+
+func init() {
+	generators.Registry["gateway"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "gateway"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("gateway", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"a Host to listen on",
+	)
+
+	fs.String(
+		"gateway.parent_ref",
+		"",
+		"the Gateway to attach the HTTPRoute to as name or namespace/name",
+	)
+
+	fs.StringSlice(
+		"gateway.hostnames",
+		[]string{},
+		"a comma-separated list of hostnames matched by the HTTPRoute, defaults to the host",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Kubernetes Gateway API HTTPRoute resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return "", fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	if opts.Gateway.ParentRef == "" {
+		return "", fmt.Errorf("gateway.parent_ref is required")
+	}
+
+	// HTTPRoute backends can only reference a Service port by number
+	if opts.Service.PortName != "" {
+		return "", fmt.Errorf("service.port_name is not supported by HTTPRoute backends, use service.port instead")
+	}
+
+	rules, err := g.generateRules(opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	if len(rules) == 0 {
+		return "", nil
+	}
+
+	resource := g.newHTTPRoute(opts, rules)
+
+	b, err := generators.Marshal(resource)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal resource: %+v: %s", resource, err.Error())
+	}
+
+	return "---\n" + string(b), nil
+}
+
+// generateRules translates each enabled spec path into an HTTPRoute match.
+// As all the matches route to the same backend, they're grouped into as few rules as the HTTPRoute limits allow.
+func (g *Generator) generateRules(opts *options.Options, spec *openapi3.T) ([]httpRouteRule, error) {
+	var matches []httpRouteMatch
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathDisabled(path) {
+			continue
+		}
+
+		matches = append(matches, httpRouteMatch{
+			Path: g.generatePathMatch(opts.Path.Base, path),
+		})
+	}
+
+	if len(matches) > maxRules*maxMatchesPerRule {
+		return nil, fmt.Errorf(
+			"%d paths exceed the %d matches a single HTTPRoute can hold, use path.include or path.exclude to reduce them",
+			len(matches),
+			maxRules*maxMatchesPerRule,
+		)
+	}
+
+	backendRef := g.backendRef(opts)
+
+	var rules []httpRouteRule
+	for start := 0; start < len(matches); start += maxMatchesPerRule {
+		end := start + maxMatchesPerRule
+		if end > len(matches) {
+			end = len(matches)
+		}
+
+		rules = append(rules, httpRouteRule{
+			Matches:     matches[start:end],
+			BackendRefs: []httpBackendRef{backendRef},
+		})
+	}
+
+	return rules, nil
+}
+
+func (g *Generator) generatePathMatch(base, path string) httpPathMatch {
+	value := base
+	if path != "/" {
+		value = strings.TrimSuffix(base, "/") + path
+	}
+
+	if openApiPathVariableRegex.MatchString(path) {
+		// quote the static parts of the path so that they are matched literally
+		parts := openApiPathVariableRegex.Split(value, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+
+		return httpPathMatch{
+			Type:  pathMatchRegularExpression,
+			Value: strings.Join(parts, `[^/]+`),
+		}
+	}
+
+	return httpPathMatch{
+		Type:  pathMatchPathPrefix,
+		Value: value,
+	}
+}
+
+// backendRef returns the reference to the target Service, including its namespace
+// only if it differs from the HTTPRoute one
+func (g *Generator) backendRef(opts *options.Options) httpBackendRef {
+	backendRef := httpBackendRef{
+		Name: opts.Service.Name,
+		Port: opts.Service.Port,
+	}
+
+	if opts.Service.Namespace != opts.Namespace {
+		backendRef.Namespace = opts.Service.Namespace
+	}
+
+	return backendRef
+}
+
+// parentRef returns the reference to gateway.parent_ref, given as name or namespace/name
+func (g *Generator) parentRef(opts *options.Options) parentReference {
+	if parts := strings.SplitN(opts.Gateway.ParentRef, "/", 2); len(parts) == 2 {
+		return parentReference{
+			Name:      parts[1],
+			Namespace: parts[0],
+		}
+	}
+
+	return parentReference{
+		Name: opts.Gateway.ParentRef,
+	}
+}
+
+// hostnames returns gateway.hostnames or, if not set, the host.
+// No hostnames are set when matching all hosts, which HTTPRoutes do by default.
+func (g *Generator) hostnames(opts *options.Options) []string {
+	if len(opts.Gateway.Hostnames) > 0 {
+		return opts.Gateway.Hostnames
+	}
+
+	if opts.Host != "" && opts.Host != "*" {
+		return []string{opts.Host}
+	}
+
+	return nil
+}
+
+func (g *Generator) newHTTPRoute(opts *options.Options, rules []httpRouteRule) httpRoute {
+	return httpRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayAPIVersion,
+			Kind:       httpRouteKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.NamePrefix + opts.Service.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: httpRouteSpec{
+			ParentRefs: []parentReference{g.parentRef(opts)},
+			Hostnames:  g.hostnames(opts),
+			Rules:      rules,
+		},
+	}
+}
@@ -0,0 +1,352 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestGateway(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			res, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestGatewayInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options options.Options
+	}{
+		{
+			name: "no parent ref",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "petstore",
+				},
+			},
+		},
+		{
+			name: "invalid parent ref",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "petstore",
+				},
+				Gateway: options.GatewayOptions{
+					ParentRef: "infra/public/http",
+				},
+			},
+		},
+		{
+			name: "match all hostname",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "petstore",
+				},
+				Gateway: options.GatewayOptions{
+					ParentRef: "public",
+					Hostnames: []string{"*"},
+				},
+			},
+		},
+		{
+			name: "port name",
+			options: options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "petstore",
+					PortName:  "http",
+				},
+				Gateway: options.GatewayOptions{
+					ParentRef: "public",
+				},
+			},
+		},
+	}
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := gen.Generate(&testCase.options, &openapi3.T{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestGatewayTooManyPaths(t *testing.T) {
+	r := require.New(t)
+
+	paths := openapi3.Paths{}
+	for i := 0; i <= maxRules*maxMatchesPerRule; i++ {
+		paths[fmt.Sprintf("/books/%d", i)] = &openapi3.PathItem{Get: &openapi3.Operation{}}
+	}
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "books",
+		},
+		Gateway: options.GatewayOptions{
+			ParentRef: "public",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{Paths: paths})
+	r.Error(err)
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "simple routes",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Gateway: options.GatewayOptions{
+				ParentRef: "public",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: HTTPRoute
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  parentRefs:
+  - name: public
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - path:
+        type: PathPrefix
+        value: /
+    - path:
+        type: RegularExpression
+        value: /pets/[^/]+
+`,
+	},
+	{
+		name: "base path, host and cross-namespace references",
+		options: options.Options{
+			Namespace: "petstore",
+			Host:      "petstore.example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+				Port:      8080,
+			},
+			Path: options.PathOptions{
+				Base: "/api",
+			},
+			Gateway: options.GatewayOptions{
+				ParentRef: "infra/public",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}/photos.json:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: HTTPRoute
+metadata:
+  name: petstore
+  namespace: petstore
+spec:
+  hostnames:
+  - petstore.example.org
+  parentRefs:
+  - name: public
+    namespace: infra
+  rules:
+  - backendRefs:
+    - name: petstore
+      namespace: default
+      port: 8080
+    matches:
+    - path:
+        type: PathPrefix
+        value: /api
+    - path:
+        type: RegularExpression
+        value: /api/pets/[^/]+/photos\.json
+`,
+	},
+	{
+		name: "hostnames override the host",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "petstore.example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Gateway: options.GatewayOptions{
+				ParentRef: "public",
+				Hostnames: []string{"petstore.example.com", "*.petstore.example.com"},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: HTTPRoute
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  hostnames:
+  - petstore.example.com
+  - '*.petstore.example.com'
+  parentRefs:
+  - name: public
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - path:
+        type: PathPrefix
+        value: /pets
+`,
+	},
+	{
+		name: "disabled path and matches grouped into rules",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Gateway: options.GatewayOptions{
+				ParentRef: "public",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/internal": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /internal:
+    get: {}
+  /pets/1:
+    get: {}
+  /pets/2:
+    get: {}
+  /pets/3:
+    get: {}
+  /pets/4:
+    get: {}
+  /pets/5:
+    get: {}
+  /pets/6:
+    get: {}
+  /pets/7:
+    get: {}
+  /pets/8:
+    get: {}
+  /pets/9:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: HTTPRoute
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  parentRefs:
+  - name: public
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - path:
+        type: PathPrefix
+        value: /pets/1
+    - path:
+        type: PathPrefix
+        value: /pets/2
+    - path:
+        type: PathPrefix
+        value: /pets/3
+    - path:
+        type: PathPrefix
+        value: /pets/4
+    - path:
+        type: PathPrefix
+        value: /pets/5
+    - path:
+        type: PathPrefix
+        value: /pets/6
+    - path:
+        type: PathPrefix
+        value: /pets/7
+    - path:
+        type: PathPrefix
+        value: /pets/8
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - path:
+        type: PathPrefix
+        value: /pets/9
+`,
+	},
+}
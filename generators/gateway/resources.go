@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	gatewayAPIVersion = "gateway.networking.k8s.io/v1beta1"
+
+	httpRouteKind = "HTTPRoute"
+
+	pathMatchPathPrefix        = "PathPrefix"
+	pathMatchRegularExpression = "RegularExpression"
+
+	// HTTPRoute validation limits
+	maxRules          = 16
+	maxMatchesPerRule = 8
+)
+
+// httpRoute is a subset of the Gateway API HTTPRoute resource
+// See https://gateway-api.sigs.k8s.io/references/spec/#gateway.networking.k8s.io/v1beta1.HTTPRoute
+type httpRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec httpRouteSpec `json:"spec"`
+}
+
+type httpRouteSpec struct {
+	ParentRefs []parentReference `json:"parentRefs"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []httpRouteRule   `json:"rules"`
+}
+
+type parentReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type httpRouteRule struct {
+	Matches     []httpRouteMatch `json:"matches"`
+	BackendRefs []httpBackendRef `json:"backendRefs"`
+}
+
+type httpRouteMatch struct {
+	Path httpPathMatch `json:"path"`
+}
+
+type httpPathMatch struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type httpBackendRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Port      int32  `json:"port"`
+}
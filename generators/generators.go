@@ -1,3 +1,91 @@
 package generators
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/options"
+)
+
 var Registry = map[string]Interface{}
+
+// OptionKeyAnnotation is a flag annotation holding the key of the option set by the flag,
+// for flags which can't be named after their option, e.g. because other flags are nested under their name.
+const OptionKeyAnnotation = "kusk_option_key"
+
+// Info describes a registered generator without exposing the generator itself
+type Info struct {
+	Cmd              string
+	ShortDescription string
+	LongDescription  string
+}
+
+// List returns the descriptions of all registered generators sorted by their command
+func List() []Info {
+	list := make([]Info, 0, len(Registry))
+	for _, gen := range Registry {
+		list = append(list, Info{
+			Cmd:              gen.Cmd(),
+			ShortDescription: gen.ShortDescription(),
+			LongDescription:  gen.LongDescription(),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Cmd < list[j].Cmd
+	})
+
+	return list
+}
+
+// Get returns the generator registered under the given name
+func Get(name string) (Interface, bool) {
+	gen, ok := Registry[name]
+	return gen, ok
+}
+
+// Defaults returns the option defaults of the generator, none for generators not implementing DefaultsProvider
+func Defaults(gen Interface) options.Options {
+	if defaultsProvider, ok := gen.(DefaultsProvider); ok {
+		return defaultsProvider.Defaults()
+	}
+
+	return options.Options{}
+}
+
+// GenerateAll runs the named generators on the same spec and returns their outputs keyed by generator command.
+// All the names have to be registered, otherwise no generator is run.
+// Each generator gets its own copy of the options, as generators fill them with defaults.
+// Failing generators don't prevent the others from running, their errors are aggregated
+// into the returned error along with the outputs of the successful ones.
+func GenerateAll(names []string, opts *options.Options, spec *openapi3.T) (map[string]string, error) {
+	for _, name := range names {
+		if _, ok := Registry[name]; !ok {
+			return nil, fmt.Errorf("unknown generator %q", name)
+		}
+	}
+
+	outputs := make(map[string]string, len(names))
+
+	var errs []string
+	for _, name := range names {
+		genOpts := *opts
+
+		output, err := Registry[name].Generate(&genOpts, spec)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+
+		outputs[name] = output
+	}
+
+	if len(errs) > 0 {
+		return outputs, fmt.Errorf("failed to generate resources: %s", strings.Join(errs, "; "))
+	}
+
+	return outputs, nil
+}
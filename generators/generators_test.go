@@ -0,0 +1,100 @@
+package generators_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/generators"
+	_ "github.com/kubeshop/kusk/generators/linkerd"
+	_ "github.com/kubeshop/kusk/generators/nginx_ingress"
+	_ "github.com/kubeshop/kusk/generators/traefik"
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+func TestList(t *testing.T) {
+	r := require.New(t)
+
+	list := generators.List()
+	r.Len(list, len(generators.Registry))
+	r.True(sort.SliceIsSorted(list, func(i, j int) bool {
+		return list[i].Cmd < list[j].Cmd
+	}))
+
+	var found bool
+	for _, info := range list {
+		if info.Cmd != "ingress-nginx" {
+			continue
+		}
+
+		found = true
+		r.NotEmpty(info.ShortDescription)
+		r.NotEmpty(info.LongDescription)
+	}
+	r.True(found, "ingress-nginx generator is not listed")
+}
+
+func TestGet(t *testing.T) {
+	r := require.New(t)
+
+	gen, ok := generators.Get("ingress-nginx")
+	r.True(ok)
+	r.Equal("ingress-nginx", gen.Cmd())
+
+	_, ok = generators.Get("non-existent")
+	r.False(ok)
+}
+
+func TestGenerateAll(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	newOptions := func() *options.Options {
+		return &options.Options{
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			},
+		}
+	}
+
+	t.Run("outputs keyed by generator", func(t *testing.T) {
+		r := require.New(t)
+
+		outputs, err := generators.GenerateAll([]string{"ingress-nginx", "linkerd"}, newOptions(), apiSpec)
+		r.NoError(err)
+		r.Len(outputs, 2)
+		r.Contains(outputs["ingress-nginx"], "kind: Ingress\n")
+		r.Contains(outputs["linkerd"], "kind: ServiceProfile\n")
+	})
+
+	t.Run("unknown generator", func(t *testing.T) {
+		r := require.New(t)
+
+		outputs, err := generators.GenerateAll([]string{"ingress-nginx", "non-existent"}, newOptions(), apiSpec)
+		r.EqualError(err, `unknown generator "non-existent"`)
+		r.Nil(outputs)
+	})
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		r := require.New(t)
+
+		opts := newOptions()
+		opts.Service.Name = ""
+
+		outputs, err := generators.GenerateAll([]string{"ingress-nginx", "linkerd"}, opts, apiSpec)
+		r.Error(err)
+		r.Contains(err.Error(), "ingress-nginx: ")
+		r.Contains(err.Error(), "linkerd: ")
+		r.Empty(outputs)
+	})
+}
@@ -0,0 +1,187 @@
+package gloo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
+)
+
+var openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+
+func init() {
+	generators.Registry["gloo"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "gloo"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("gloo", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"a Host to listen on",
+	)
+
+	fs.String(
+		"gloo.upstream",
+		"",
+		"the Upstream to route to as name or namespace/name, defaults to the one discovered for the Service",
+	)
+
+	fs.String(
+		"gloo.namespace",
+		"",
+		"namespace for the generated VirtualService, overrides --namespace",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Gloo Edge VirtualService resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return "", fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	routes := g.generateRoutes(opts, spec)
+	if len(routes) == 0 {
+		return "", nil
+	}
+
+	resource := g.newVirtualService(opts, routes)
+
+	b, err := generators.Marshal(resource)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal resource: %+v: %s", resource, err.Error())
+	}
+
+	return "---\n" + string(b), nil
+}
+
+// generateRoutes translates each enabled spec path into a VirtualService route
+func (g *Generator) generateRoutes(opts *options.Options, spec *openapi3.T) []route {
+	upstream := g.upstream(opts)
+
+	var routes []route
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathDisabled(path) {
+			continue
+		}
+
+		routes = append(routes, route{
+			Matchers: []matcher{g.generateMatcher(opts.Path.Base, path)},
+			RouteAction: routeAction{
+				Single: destination{
+					Upstream: upstream,
+				},
+			},
+		})
+	}
+
+	return routes
+}
+
+func (g *Generator) generateMatcher(base, path string) matcher {
+	uri := base
+	if path != "/" {
+		uri = strings.TrimSuffix(base, "/") + path
+	}
+
+	if openApiPathVariableRegex.MatchString(path) {
+		// quote the static parts of the path so that they are matched literally,
+		// Gloo regex matchers have to match the full path so no anchors are needed
+		parts := openApiPathVariableRegex.Split(uri, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+
+		return matcher{
+			Regex: strings.Join(parts, `[^/]+`),
+		}
+	}
+
+	return matcher{
+		Exact: uri,
+	}
+}
+
+// upstream returns the reference to gloo.upstream or, if not set, to the Upstream
+// Gloo discovery creates for the Service, named <namespace>-<name>-<port>
+func (g *Generator) upstream(opts *options.Options) resourceRef {
+	if opts.Gloo.Upstream == "" {
+		return resourceRef{
+			Name:      fmt.Sprintf("%s-%s-%d", opts.Service.Namespace, opts.Service.Name, opts.Service.Port),
+			Namespace: discoveryNamespace,
+		}
+	}
+
+	if parts := strings.SplitN(opts.Gloo.Upstream, "/", 2); len(parts) == 2 {
+		return resourceRef{
+			Name:      parts[1],
+			Namespace: parts[0],
+		}
+	}
+
+	return resourceRef{
+		Name:      opts.Gloo.Upstream,
+		Namespace: discoveryNamespace,
+	}
+}
+
+func (g *Generator) domains(opts *options.Options) []string {
+	if opts.Host != "" {
+		return []string{opts.Host}
+	}
+
+	return []string{"*"}
+}
+
+func (g *Generator) newVirtualService(opts *options.Options, routes []route) virtualService {
+	namespace := opts.Gloo.Namespace
+	if namespace == "" {
+		namespace = opts.Namespace
+	}
+
+	return virtualService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: glooAPIVersion,
+			Kind:       virtualServiceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.NamePrefix + opts.Service.Name,
+			Namespace: namespace,
+		},
+		Spec: virtualServiceSpec{
+			VirtualHost: virtualHost{
+				Domains: g.domains(opts),
+				Routes:  routes,
+			},
+		},
+	}
+}
@@ -0,0 +1,208 @@
+package gloo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestGloo(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			res, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestGlooInvalidUpstream(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "petstore",
+		},
+		Gloo: options.GlooOptions{
+			Upstream: "gloo-system/petstore/80",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "simple routes",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.solo.io/v1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  virtualHost:
+    domains:
+    - '*'
+    routes:
+    - matchers:
+      - exact: /
+      routeAction:
+        single:
+          upstream:
+            name: default-petstore-80
+            namespace: gloo-system
+    - matchers:
+      - regex: /pets/[^/]+
+      routeAction:
+        single:
+          upstream:
+            name: default-petstore-80
+            namespace: gloo-system
+`,
+	},
+	{
+		name: "host, base path, upstream and namespace overrides",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Path: options.PathOptions{
+				Base: "/api/v1.0/",
+			},
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+				Port:      8080,
+			},
+			Gloo: options.GlooOptions{
+				Upstream:  "petstore/petstore-upstream",
+				Namespace: "gloo-system",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets:
+    get: {}
+
+  /pets/{petId}/tags/{tag}:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.solo.io/v1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: gloo-system
+spec:
+  virtualHost:
+    domains:
+    - example.org
+    routes:
+    - matchers:
+      - exact: /api/v1.0/
+      routeAction:
+        single:
+          upstream:
+            name: petstore-upstream
+            namespace: petstore
+    - matchers:
+      - exact: /api/v1.0/pets
+      routeAction:
+        single:
+          upstream:
+            name: petstore-upstream
+            namespace: petstore
+    - matchers:
+      - regex: /api/v1\.0/pets/[^/]+/tags/[^/]+
+      routeAction:
+        single:
+          upstream:
+            name: petstore-upstream
+            namespace: petstore
+`,
+	},
+	{
+		name: "disabled path is excluded",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/internal": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+
+  /internal:
+    get: {}
+`,
+		res: `---
+apiVersion: gateway.solo.io/v1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  virtualHost:
+    domains:
+    - '*'
+    routes:
+    - matchers:
+      - exact: /pets
+      routeAction:
+        single:
+          upstream:
+            name: default-petstore-80
+            namespace: gloo-system
+`,
+	},
+}
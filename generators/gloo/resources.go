@@ -0,0 +1,55 @@
+package gloo
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	glooAPIVersion = "gateway.solo.io/v1"
+
+	virtualServiceKind = "VirtualService"
+
+	// discoveryNamespace is the namespace Gloo discovery creates Upstreams in
+	discoveryNamespace = "gloo-system"
+)
+
+// virtualService is a subset of the Gloo Edge VirtualService resource
+// See https://docs.solo.io/gloo-edge/latest/reference/api/github.com/solo-io/gloo/projects/gateway/api/v1/virtual_service.proto.sk/
+type virtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec virtualServiceSpec `json:"spec"`
+}
+
+type virtualServiceSpec struct {
+	VirtualHost virtualHost `json:"virtualHost"`
+}
+
+type virtualHost struct {
+	Domains []string `json:"domains"`
+	Routes  []route  `json:"routes,omitempty"`
+}
+
+type route struct {
+	Matchers    []matcher   `json:"matchers"`
+	RouteAction routeAction `json:"routeAction"`
+}
+
+type matcher struct {
+	Exact string `json:"exact,omitempty"`
+	Regex string `json:"regex,omitempty"`
+}
+
+type routeAction struct {
+	Single destination `json:"single"`
+}
+
+type destination struct {
+	Upstream resourceRef `json:"upstream"`
+}
+
+type resourceRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
@@ -3,6 +3,7 @@ package generators
 import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kubeshop/kusk/options"
 )
@@ -16,3 +17,41 @@ type Interface interface {
 
 	Generate(options *options.Options, spec *openapi3.T) (string, error)
 }
+
+// FilesGenerator is implemented by generators able to output each generated resource into a separate file
+type FilesGenerator interface {
+	GenerateFiles(options *options.Options, spec *openapi3.T) (Files, error)
+}
+
+// ResourcesGenerator is implemented by generators able to return the generated resources as typed Kubernetes objects,
+// e.g. for library consumers to inspect or mutate them before applying
+type ResourcesGenerator interface {
+	GenerateResources(options *options.Options, spec *openapi3.T) ([]runtime.Object, error)
+}
+
+// DefaultsProvider is implemented by generators with option defaults of their own, e.g. another path type.
+// The defaults are overridden by the x-kusk extension, the config file and the flags, keeping them out of the shared ones.
+type DefaultsProvider interface {
+	Defaults() options.Options
+}
+
+// PathsLister is implemented by generators able to list the routes they would generate for the spec paths,
+// e.g. to debug the path filtering without generating the resources
+type PathsLister interface {
+	ListPaths(options *options.Options, spec *openapi3.T) ([]Path, error)
+}
+
+// Path is an included spec path along with the route generated for it
+type Path struct {
+	// SpecPath is the path as declared in the spec, empty for the route of path.default
+	SpecPath string
+
+	// Path is the path matched by the generated route
+	Path string
+
+	// PathType is the type of Path, e.g. Exact or Prefix
+	PathType string
+
+	// Name is the name of the resource holding the route
+	Name string
+}
@@ -0,0 +1,273 @@
+package istio
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
+)
+
+var (
+	openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+
+	// gatewaySelector selects the default Istio ingress gateway deployment
+	gatewaySelector = map[string]string{"istio": "ingressgateway"}
+)
+
+func init() {
+	generators.Registry["istio"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "istio"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("istio", pflag.ExitOnError)
+
+	fs.String(
+		"cluster.cluster_domain",
+		"cluster.local",
+		"kubernetes cluster domain",
+	)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"a Host to listen on",
+	)
+
+	fs.Uint32(
+		"timeouts.request_timeout",
+		0,
+		"total request timeout (seconds)",
+	)
+
+	fs.Int(
+		"retries.attempts",
+		0,
+		"the number of times a failed request is retried",
+	)
+
+	fs.Uint32(
+		"retries.per_try_timeout",
+		0,
+		"the timeout of each attempt (seconds)",
+	)
+
+	fs.String(
+		"istio.gateway",
+		"",
+		"the name of an existing Gateway to bind the VirtualService to",
+	)
+
+	fs.Bool(
+		"istio.generate_gateway",
+		false,
+		"additionally generate a Gateway bound to the host",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Istio VirtualService resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return "", fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	routes := g.generateRoutes(opts, spec)
+	if len(routes) == 0 {
+		return "", nil
+	}
+
+	var resources []interface{}
+
+	gatewayName := opts.Istio.Gateway
+	if opts.Istio.GenerateGateway {
+		if gatewayName == "" {
+			gatewayName = fmt.Sprintf("%s%s-gateway", opts.NamePrefix, opts.Service.Name)
+		}
+
+		resources = append(resources, g.newGateway(gatewayName, opts))
+	}
+
+	resources = append(resources, g.newVirtualService(opts, gatewayName, routes))
+
+	var builder strings.Builder
+
+	for _, resource := range resources {
+		builder.WriteString("---\n") // indicate start of YAML resource
+		b, err := generators.Marshal(resource)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal resource: %+v: %s", resource, err.Error())
+		}
+		builder.WriteString(string(b))
+	}
+
+	return builder.String(), nil
+}
+
+// generateRoutes translates each enabled spec path into a VirtualService HTTP route
+func (g *Generator) generateRoutes(opts *options.Options, spec *openapi3.T) []httpRoute {
+	var specPaths []string
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathDisabled(path) {
+			continue
+		}
+
+		specPaths = append(specPaths, path)
+	}
+
+	host := opts.Service.FQDN(opts.Cluster.ClusterDomain)
+
+	var timeout string
+	if requestTimeout := opts.Timeouts.RequestTimeout; requestTimeout > 0 {
+		timeout = fmt.Sprintf("%ds", requestTimeout)
+	}
+
+	routes := make([]httpRoute, 0, len(specPaths))
+	for _, path := range specPaths {
+		routes = append(routes, httpRoute{
+			Name: path,
+			Match: []httpMatchRequest{
+				{
+					URI: g.generateURIMatch(opts.Path.Base, path),
+				},
+			},
+			Route: []httpRouteDestination{
+				{
+					Destination: destination{
+						Host: host,
+						Port: portSelector{
+							Number: uint32(opts.Service.Port),
+						},
+					},
+				},
+			},
+			Timeout: timeout,
+			Retries: g.generateRetries(opts.GetRetryOpts(path, "")),
+		})
+	}
+
+	return routes
+}
+
+// generateRetries returns the retry policy of a route or nil if retries are not configured
+func (g *Generator) generateRetries(retryOpts options.RetryOptions) *httpRetry {
+	if retryOpts.Attempts == 0 {
+		return nil
+	}
+
+	retries := &httpRetry{
+		Attempts: retryOpts.Attempts,
+	}
+
+	if retryOpts.PerTryTimeout > 0 {
+		retries.PerTryTimeout = fmt.Sprintf("%ds", retryOpts.PerTryTimeout)
+	}
+
+	return retries
+}
+
+func (g *Generator) generateURIMatch(base, path string) stringMatch {
+	uri := base
+	if path != "/" {
+		uri = strings.TrimSuffix(base, "/") + path
+	}
+
+	if openApiPathVariableRegex.MatchString(path) {
+		// quote the static parts of the path so that they are matched literally
+		parts := openApiPathVariableRegex.Split(uri, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+
+		return stringMatch{
+			Regex: "^" + strings.Join(parts, `[^/]+`) + "$",
+		}
+	}
+
+	return stringMatch{
+		Exact: uri,
+	}
+}
+
+func (g *Generator) hosts(opts *options.Options) []string {
+	if opts.Host != "" {
+		return []string{opts.Host}
+	}
+
+	return []string{"*"}
+}
+
+func (g *Generator) newVirtualService(opts *options.Options, gatewayName string, routes []httpRoute) virtualService {
+	var gateways []string
+	if gatewayName != "" {
+		gateways = []string{gatewayName}
+	}
+
+	return virtualService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: istioAPIVersion,
+			Kind:       virtualServiceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.NamePrefix + opts.Service.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: virtualServiceSpec{
+			Hosts:    g.hosts(opts),
+			Gateways: gateways,
+			HTTP:     routes,
+		},
+	}
+}
+
+func (g *Generator) newGateway(name string, opts *options.Options) gateway {
+	return gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: istioAPIVersion,
+			Kind:       gatewayKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: opts.Namespace,
+		},
+		Spec: gatewaySpec{
+			Selector: gatewaySelector,
+			Servers: []gatewayServer{
+				{
+					Port: gatewayPort{
+						Number:   80,
+						Name:     "http",
+						Protocol: "HTTP",
+					},
+					Hosts: g.hosts(opts),
+				},
+			},
+		},
+	}
+}
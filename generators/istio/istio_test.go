@@ -0,0 +1,350 @@
+package istio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestIstio(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			res, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "simple routes",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  hosts:
+  - '*'
+  http:
+  - match:
+    - uri:
+        exact: /
+    name: /
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+  - match:
+    - uri:
+        regex: ^/pets/[^/]+$
+    name: /pets/{petId}
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+`,
+	},
+	{
+		name: "base path, existing gateway and timeout",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Path: options.PathOptions{
+				Base: "/api",
+			},
+			Istio: options.IstioOptions{
+				Gateway: "istio-system/public",
+			},
+			Timeouts: options.TimeoutOptions{
+				RequestTimeout: 30,
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}/photos.json:
+    get: {}
+`,
+		res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  gateways:
+  - istio-system/public
+  hosts:
+  - example.org
+  http:
+  - match:
+    - uri:
+        exact: /api
+    name: /
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+    timeout: 30s
+  - match:
+    - uri:
+        regex: ^/api/pets/[^/]+/photos\.json$
+    name: /pets/{petId}/photos.json
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+    timeout: 30s
+`,
+	},
+	{
+		name: "generated gateway",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Istio: options.IstioOptions{
+				GenerateGateway: true,
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: Gateway
+metadata:
+  name: petstore-gateway
+  namespace: default
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - hosts:
+    - example.org
+    port:
+      name: http
+      number: 80
+      protocol: HTTP
+---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  gateways:
+  - petstore-gateway
+  hosts:
+  - example.org
+  http:
+  - match:
+    - uri:
+        exact: /pets
+    name: /pets
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+`,
+	},
+	{
+		name: "disabled path is excluded",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/internal": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+
+  /internal:
+    get: {}
+`,
+		res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  hosts:
+  - '*'
+  http:
+  - match:
+    - uri:
+        exact: /pets
+    name: /pets
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+`,
+	},
+	{
+		name: "retries with a path override",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Retries: options.RetryOptions{
+				Attempts:      3,
+				PerTryTimeout: 2,
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/pets/{petId}": {
+					Retries: options.RetryOptions{
+						Attempts: 1,
+					},
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  hosts:
+  - '*'
+  http:
+  - match:
+    - uri:
+        exact: /pets
+    name: /pets
+    retries:
+      attempts: 3
+      perTryTimeout: 2s
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+  - match:
+    - uri:
+        regex: ^/pets/[^/]+$
+    name: /pets/{petId}
+    retries:
+      attempts: 1
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+`,
+	},
+	{
+		name: "custom cluster domain",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Cluster: options.ClusterOptions{
+				ClusterDomain: "cluster.internal",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+`,
+		res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: petstore
+  namespace: default
+spec:
+  hosts:
+  - '*'
+  http:
+  - match:
+    - uri:
+        exact: /
+    name: /
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.internal
+        port:
+          number: 80
+`,
+	},
+}
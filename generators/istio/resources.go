@@ -0,0 +1,88 @@
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	istioAPIVersion = "networking.istio.io/v1beta1"
+
+	virtualServiceKind = "VirtualService"
+	gatewayKind        = "Gateway"
+)
+
+// virtualService is a subset of the Istio VirtualService resource
+// See https://istio.io/latest/docs/reference/config/networking/virtual-service/
+type virtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec virtualServiceSpec `json:"spec"`
+}
+
+type virtualServiceSpec struct {
+	Hosts    []string    `json:"hosts,omitempty"`
+	Gateways []string    `json:"gateways,omitempty"`
+	HTTP     []httpRoute `json:"http,omitempty"`
+}
+
+type httpRoute struct {
+	Name    string                 `json:"name,omitempty"`
+	Match   []httpMatchRequest     `json:"match,omitempty"`
+	Route   []httpRouteDestination `json:"route,omitempty"`
+	Timeout string                 `json:"timeout,omitempty"`
+	Retries *httpRetry             `json:"retries,omitempty"`
+}
+
+type httpRetry struct {
+	Attempts      int    `json:"attempts"`
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+}
+
+type httpMatchRequest struct {
+	URI stringMatch `json:"uri"`
+}
+
+type stringMatch struct {
+	Exact  string `json:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+type httpRouteDestination struct {
+	Destination destination `json:"destination"`
+}
+
+type destination struct {
+	Host string       `json:"host"`
+	Port portSelector `json:"port"`
+}
+
+type portSelector struct {
+	Number uint32 `json:"number"`
+}
+
+// gateway is a subset of the Istio Gateway resource
+// See https://istio.io/latest/docs/reference/config/networking/gateway/
+type gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec gatewaySpec `json:"spec"`
+}
+
+type gatewaySpec struct {
+	Selector map[string]string `json:"selector"`
+	Servers  []gatewayServer   `json:"servers"`
+}
+
+type gatewayServer struct {
+	Port  gatewayPort `json:"port"`
+	Hosts []string    `json:"hosts"`
+}
+
+type gatewayPort struct {
+	Number   uint32 `json:"number"`
+	Name     string `json:"name"`
+	Protocol string `json:"protocol"`
+}
@@ -0,0 +1,226 @@
+package kong
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
+)
+
+const (
+	ingressAPIVersion = "networking.k8s.io/v1"
+	ingressKind       = "Ingress"
+
+	overrideAnnotationKey  = "konghq.com/override"
+	pluginsAnnotationKey   = "konghq.com/plugins"
+	stripPathAnnotationKey = "konghq.com/strip-path"
+)
+
+var (
+	ingressClassName = "kong"
+
+	openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+)
+
+func init() {
+	generators.Registry["kong"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "kong"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("kong", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"an Ingress Host to listen on",
+	)
+
+	fs.Uint32(
+		"timeouts.request_timeout",
+		0,
+		"total request timeout (seconds)",
+	)
+
+	fs.StringSlice(
+		"kong.plugins",
+		[]string{},
+		"a KongPlugin name to apply to the routes, can be repeated",
+	)
+
+	fs.Bool(
+		"kong.strip_path",
+		false,
+		"strip the matched route path before forwarding to the upstream Service",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Kong Ingress resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return "", fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	paths := g.generatePaths(opts, spec)
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	name := fmt.Sprintf("%s%s-ingress", opts.NamePrefix, opts.Service.Name)
+
+	kongIngress := g.newKongIngress(name, opts)
+	ingress := g.newIngressResource(name, opts, paths)
+
+	var builder strings.Builder
+
+	for _, resource := range []interface{}{kongIngress, ingress} {
+		builder.WriteString("---\n") // indicate start of YAML resource
+		b, err := generators.Marshal(resource)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal resource: %+v: %s", resource, err.Error())
+		}
+		builder.WriteString(string(b))
+	}
+
+	return builder.String(), nil
+}
+
+// generatePaths translates each enabled spec path into an Ingress path i.e. Kong route
+func (g *Generator) generatePaths(opts *options.Options, spec *openapi3.T) []v1.HTTPIngressPath {
+	var specPaths []string
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathDisabled(path) {
+			continue
+		}
+
+		specPaths = append(specPaths, path)
+	}
+
+	paths := make([]v1.HTTPIngressPath, 0, len(specPaths))
+	for _, path := range specPaths {
+		pathType := v1.PathTypeExact
+		ingressPath := opts.Path.Base
+		if path != "/" {
+			ingressPath = strings.TrimSuffix(opts.Path.Base, "/") + path
+		}
+
+		// Kong treats paths containing regex symbols as regular expressions
+		if openApiPathVariableRegex.MatchString(path) {
+			pathType = v1.PathTypeImplementationSpecific
+			ingressPath = openApiPathVariableRegex.ReplaceAllString(ingressPath, `[^/]+`)
+		}
+
+		paths = append(paths, v1.HTTPIngressPath{
+			Path:     ingressPath,
+			PathType: &pathType,
+			Backend: v1.IngressBackend{
+				Service: &v1.IngressServiceBackend{
+					Name: opts.Service.Name,
+					Port: v1.ServiceBackendPort{
+						Number: opts.Service.Port,
+					},
+				},
+			},
+		})
+	}
+
+	return paths
+}
+
+func (g *Generator) newKongIngress(name string, opts *options.Options) kongIngress {
+	stripPath := opts.Kong.StripPath
+
+	res := kongIngress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kongIngressAPIVersion,
+			Kind:       kongIngressKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: opts.Namespace,
+		},
+		Route: &kongIngressRoute{
+			StripPath: &stripPath,
+		},
+	}
+
+	if requestTimeout := opts.Timeouts.RequestTimeout; requestTimeout > 0 {
+		res.Proxy = &kongIngressProxy{
+			ReadTimeout:  requestTimeout * 1000,
+			WriteTimeout: requestTimeout * 1000,
+		}
+	}
+
+	return res
+}
+
+func (g *Generator) newIngressResource(name string, opts *options.Options, paths []v1.HTTPIngressPath) v1.Ingress {
+	annotations := map[string]string{
+		overrideAnnotationKey:  name,
+		stripPathAnnotationKey: strconv.FormatBool(opts.Kong.StripPath),
+	}
+
+	if len(opts.Kong.Plugins) > 0 {
+		annotations[pluginsAnnotationKey] = strings.Join(opts.Kong.Plugins, ",")
+	}
+
+	// a rule without a host matches all of them, * isn't a valid Ingress host
+	host := opts.Host
+	if host == "*" {
+		host = ""
+	}
+
+	return v1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ingressAPIVersion,
+			Kind:       ingressKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   opts.Namespace,
+			Annotations: annotations,
+		},
+		Spec: v1.IngressSpec{
+			IngressClassName: &ingressClassName,
+			Rules: []v1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: v1.IngressRuleValue{
+						HTTP: &v1.HTTPIngressRuleValue{
+							Paths: paths,
+						},
+					},
+				},
+			},
+		},
+	}
+}
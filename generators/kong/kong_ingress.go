@@ -0,0 +1,31 @@
+package kong
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	kongIngressAPIVersion = "configuration.konghq.com/v1"
+	kongIngressKind       = "KongIngress"
+)
+
+// kongIngress is a subset of the KongIngress custom resource
+// See https://docs.konghq.com/kubernetes-ingress-controller/latest/references/custom-resources/#kongingress
+type kongIngress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Route *kongIngressRoute `json:"route,omitempty"`
+	Proxy *kongIngressProxy `json:"proxy,omitempty"`
+}
+
+type kongIngressRoute struct {
+	StripPath *bool    `json:"strip_path,omitempty"`
+	Protocols []string `json:"protocols,omitempty"`
+}
+
+type kongIngressProxy struct {
+	// timeouts are in milliseconds
+	ReadTimeout  uint32 `json:"read_timeout,omitempty"`
+	WriteTimeout uint32 `json:"write_timeout,omitempty"`
+}
@@ -0,0 +1,283 @@
+package kong
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk/options"
+	"github.com/kubeshop/kusk/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestKong(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			res, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "match all hosts",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "*",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: configuration.konghq.com/v1
+kind: KongIngress
+metadata:
+  name: petstore-ingress
+  namespace: default
+route:
+  strip_path: false
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/override: petstore-ingress
+    konghq.com/strip-path: "false"
+  name: petstore-ingress
+  namespace: default
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /pets
+        pathType: Exact
+`,
+	},
+	{
+		name: "simple routes",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets/{petId}:
+    get: {}
+`,
+		res: `---
+apiVersion: configuration.konghq.com/v1
+kind: KongIngress
+metadata:
+  name: petstore-ingress
+  namespace: default
+route:
+  strip_path: false
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/override: petstore-ingress
+    konghq.com/strip-path: "false"
+  name: petstore-ingress
+  namespace: default
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /
+        pathType: Exact
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /pets/[^/]+
+        pathType: ImplementationSpecific
+`,
+	},
+	{
+		name: "plugins, strip path, base path, host and timeouts",
+		options: options.Options{
+			Namespace: "default",
+			Host:      "example.org",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			Path: options.PathOptions{
+				Base: "/api",
+			},
+			Kong: options.KongOptions{
+				Plugins:   []string{"rate-limit", "auth"},
+				StripPath: true,
+			},
+			Timeouts: options.TimeoutOptions{
+				RequestTimeout: 30,
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+
+  /pets:
+    get: {}
+`,
+		res: `---
+apiVersion: configuration.konghq.com/v1
+kind: KongIngress
+metadata:
+  name: petstore-ingress
+  namespace: default
+proxy:
+  read_timeout: 30000
+  write_timeout: 30000
+route:
+  strip_path: true
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/override: petstore-ingress
+    konghq.com/plugins: rate-limit,auth
+    konghq.com/strip-path: "true"
+  name: petstore-ingress
+  namespace: default
+spec:
+  ingressClassName: kong
+  rules:
+  - host: example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /api
+        pathType: Exact
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /api/pets
+        pathType: Exact
+`,
+	},
+	{
+		name: "disabled path is excluded",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/internal": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+
+  /internal:
+    get: {}
+`,
+		res: `---
+apiVersion: configuration.konghq.com/v1
+kind: KongIngress
+metadata:
+  name: petstore-ingress
+  namespace: default
+route:
+  strip_path: false
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/override: petstore-ingress
+    konghq.com/strip-path: "false"
+  name: petstore-ingress
+  namespace: default
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /pets
+        pathType: Exact
+`,
+	},
+	{
+		name: "all paths disabled",
+		options: options.Options{
+			Namespace: "default",
+			Disabled:  true,
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pets:
+    get: {}
+`,
+		res: "",
+	},
+}
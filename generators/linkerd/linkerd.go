@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha2"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/profiles"
@@ -16,6 +15,7 @@ import (
 
 	"github.com/kubeshop/kusk/generators"
 	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
 )
 
 func init() {
@@ -76,18 +76,13 @@ func (g *Generator) Generate(options *options.Options, spec *openapi3.T) (string
 			Kind:       k8s.ServiceProfileKind,
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: fmt.Sprintf(
-				"%s.%s.svc.%s",
-				options.Service.Name,
-				options.Service.Namespace,
-				options.Cluster.ClusterDomain,
-			),
+			Name:      options.Service.FQDN(options.Cluster.ClusterDomain),
 			Namespace: options.Namespace,
 		},
 		Spec: spSpec,
 	}
 
-	b, err := yaml.Marshal(profile)
+	b, err := generators.Marshal(profile)
 
 	return string(b), err
 }
@@ -95,13 +90,14 @@ func (g *Generator) Generate(options *options.Options, spec *openapi3.T) (string
 func (g *Generator) generateServiceProfileSpec(options *options.Options, spec *openapi3.T) v1alpha2.ServiceProfileSpec {
 	routes := make([]*v1alpha2.RouteSpec, 0)
 
-	for path, pathItem := range spec.Paths {
-		for method, _ := range pathItem.Operations() {
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
+		for method, operation := range pathItem.Operations() {
 			if options.IsOperationDisabled(path, method) {
 				continue
 			}
 
-			routes = append(routes, generateRouteSpec(method, path, options))
+			routes = append(routes, generateRouteSpec(method, path, operation, options))
 		}
 	}
 
@@ -112,13 +108,20 @@ func (g *Generator) generateServiceProfileSpec(options *options.Options, spec *o
 	return v1alpha2.ServiceProfileSpec{Routes: routes}
 }
 
-func generateRouteSpec(method, path string, opts *options.Options) *v1alpha2.RouteSpec {
-	path = strings.TrimSuffix(opts.Path.Base, "/") + "/" + strings.TrimPrefix(path, "/")
+// generateRouteSpec returns the route of an operation, named after its operationId,
+// or its method and path if the operation has none
+func generateRouteSpec(method, path string, operation *openapi3.Operation, opts *options.Options) *v1alpha2.RouteSpec {
+	routePath := strings.TrimSuffix(opts.Path.Base, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	name := fmt.Sprintf("%s %s", method, routePath)
+	if operation.OperationID != "" {
+		name = operation.OperationID
+	}
 
 	res := &v1alpha2.RouteSpec{
-		Name: fmt.Sprintf("%s %s", method, path),
+		Name: name,
 		Condition: &v1alpha2.RequestMatch{
-			PathRegex: profiles.PathToRegex(path),
+			PathRegex: profiles.PathToRegex(routePath),
 			Method:    method,
 		},
 	}
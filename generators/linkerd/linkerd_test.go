@@ -62,7 +62,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -106,7 +105,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -164,7 +162,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -237,7 +234,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -307,7 +303,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -356,7 +351,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -412,7 +406,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -468,7 +461,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: webapp.default.svc.cluster.local
   namespace: default
 spec:
@@ -545,7 +537,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: petstore.default.svc.cluster.local
   namespace: default
 spec:
@@ -596,7 +587,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: petstore.default.svc.cluster.local
   namespace: default
 spec:
@@ -641,7 +631,6 @@ paths:
 		res: `apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
-  creationTimestamp: null
   name: petstore.default.svc.cluster.local
   namespace: default
 spec:
@@ -654,6 +643,116 @@ spec:
       method: POST
       pathRegex: /
     name: POST /
+`,
+	},
+	{
+		name: "routes named after operation ids",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+			},
+			Cluster: options.ClusterOptions{
+				ClusterDomain: "cluster.local",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /authors:
+    get:
+      operationId: listAuthors
+    post: {}
+`,
+		res: `apiVersion: linkerd.io/v1alpha2
+kind: ServiceProfile
+metadata:
+  name: webapp.default.svc.cluster.local
+  namespace: default
+spec:
+  routes:
+  - condition:
+      method: POST
+      pathRegex: /authors
+    name: POST /authors
+  - condition:
+      method: GET
+      pathRegex: /authors
+    name: listAuthors
+`,
+	},
+	{
+		name: "path-level timeout with prefix",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+			},
+			Cluster: options.ClusterOptions{
+				ClusterDomain: "cluster.local",
+			},
+			Path: options.PathOptions{
+				Base: "/prefix",
+			},
+			PathSubOptions: map[string]options.SubOptions{
+				"/authors": {
+					Timeouts: options.TimeoutOptions{
+						RequestTimeout: 6,
+					},
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /authors:
+    x-kusk:
+      timeouts:
+        request_timeout: 6
+    post: {}
+`,
+		res: `apiVersion: linkerd.io/v1alpha2
+kind: ServiceProfile
+metadata:
+  name: webapp.default.svc.cluster.local
+  namespace: default
+spec:
+  routes:
+  - condition:
+      method: POST
+      pathRegex: /prefix/authors
+    name: POST /prefix/authors
+    timeout: 6s
+`,
+	},
+	{
+		name: "custom cluster domain",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "books",
+				Name:      "webapp",
+			},
+			Cluster: options.ClusterOptions{
+				ClusterDomain: "cluster.internal",
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+`,
+		res: `apiVersion: linkerd.io/v1alpha2
+kind: ServiceProfile
+metadata:
+  name: webapp.books.svc.cluster.internal
+  namespace: default
+spec:
+  routes:
+  - condition:
+      method: GET
+      pathRegex: /
+    name: GET /
 `,
 	},
 }
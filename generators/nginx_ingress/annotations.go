@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
+	v1 "k8s.io/api/networking/v1"
+
 	"github.com/kubeshop/kusk/options"
 )
 
@@ -17,14 +20,41 @@ const (
 	corsEnableAnnotationKey = "nginx.ingress.kubernetes.io/enable-cors"
 
 	useRegexAnnotationKey = "nginx.ingress.kubernetes.io/use-regex"
+
+	configurationSnippetAnnotationKey = "nginx.ingress.kubernetes.io/configuration-snippet"
+
+	sslRedirectAnnotationKey      = "nginx.ingress.kubernetes.io/ssl-redirect"
+	forceSSLRedirectAnnotationKey = "nginx.ingress.kubernetes.io/force-ssl-redirect"
+
+	proxyBodySizeAnnotationKey  = "nginx.ingress.kubernetes.io/proxy-body-size"
+	proxyBufferingAnnotationKey = "nginx.ingress.kubernetes.io/proxy-buffering"
+
+	affinityAnnotationKey             = "nginx.ingress.kubernetes.io/affinity"
+	sessionCookieNameAnnotationKey    = "nginx.ingress.kubernetes.io/session-cookie-name"
+	sessionCookieExpiresAnnotationKey = "nginx.ingress.kubernetes.io/session-cookie-expires"
+
+	proxyReadTimeoutAnnotationKey = "nginx.ingress.kubernetes.io/proxy-read-timeout"
+	proxySendTimeoutAnnotationKey = "nginx.ingress.kubernetes.io/proxy-send-timeout"
+
+	whitelistSourceRangeAnnotationKey = "nginx.ingress.kubernetes.io/whitelist-source-range"
+
+	backendProtocolAnnotationKey = "nginx.ingress.kubernetes.io/backend-protocol"
+
+	// websocketTimeout is the number of seconds an idle websocket connection is kept open
+	websocketTimeout = "3600"
+
+	managedByLabelKey   = "app.kubernetes.io/managed-by"
+	managedByLabelValue = "kusk"
 )
 
 func (g *Generator) generateAnnotations(
+	controller controllerAnnotations,
 	path *options.PathOptions,
 	nginx *options.NGINXIngressOptions,
 	cors *options.CORSOptions,
 	rateLimits *options.RateLimitOptions,
 	timeoutOpts *options.TimeoutOptions,
+	retryOpts *options.RetryOptions,
 ) map[string]string {
 	annotations := map[string]string{}
 
@@ -34,7 +64,58 @@ func (g *Generator) generateAnnotations(
 		annotations[rewriteTargetAnnotationKey] = "/$2"
 	}
 
-	// CORS
+	controller.ingress(annotations)
+	controller.cors(annotations, cors)
+	controller.rateLimits(annotations, rateLimits)
+	controller.timeouts(annotations, timeoutOpts)
+	controller.retries(annotations, retryOpts)
+
+	return annotations
+}
+
+// controllerAnnotations generates the annotations of the options shared by the ingress controllers
+// in the vocabulary of one of them, selected with ingress.controller
+type controllerAnnotations interface {
+	// ingress adds the controller specific annotations of every Ingress
+	ingress(annotations map[string]string)
+	cors(annotations map[string]string, cors *options.CORSOptions)
+	rateLimits(annotations map[string]string, rateLimits *options.RateLimitOptions)
+	timeouts(annotations map[string]string, timeoutOpts *options.TimeoutOptions)
+	retries(annotations map[string]string, retryOpts *options.RetryOptions)
+}
+
+// controllerAnnotationsFor returns the annotations vocabulary of the ingress.controller, ingress-nginx by default
+func controllerAnnotationsFor(opts *options.Options) controllerAnnotations {
+	switch opts.Ingress.Controller {
+	case options.IngressControllerHAProxy:
+		return haproxyAnnotations{}
+	case options.IngressControllerTraefik:
+		return traefikAnnotations{}
+	case options.IngressControllerALB:
+		return albAnnotations{albOpts: &opts.ALB}
+	default:
+		return nginxAnnotations{}
+	}
+}
+
+// nginxAnnotations generates ingress-nginx annotations
+type nginxAnnotations struct{}
+
+func (nginxAnnotations) ingress(map[string]string) {}
+
+func (nginxAnnotations) retries(annotations map[string]string, retryOpts *options.RetryOptions) {
+	if attempts := retryOpts.Attempts; attempts > 0 {
+		// the number of tries includes the initial attempt
+		annotations["nginx.ingress.kubernetes.io/proxy-next-upstream-tries"] = strconv.Itoa(attempts + 1)
+
+		// ingress-nginx has no per try timeout, limit the time spent on all attempts instead
+		if perTryTimeout := retryOpts.PerTryTimeout; perTryTimeout > 0 {
+			annotations["nginx.ingress.kubernetes.io/proxy-next-upstream-timeout"] = strconv.Itoa(int(perTryTimeout) * (attempts + 1))
+		}
+	}
+}
+
+func (nginxAnnotations) cors(annotations map[string]string, cors *options.CORSOptions) {
 	if origins := cors.Origins; len(origins) > 0 {
 		if len(origins) > 1 {
 			log.
@@ -70,9 +151,9 @@ func (g *Generator) generateAnnotations(
 		annotations[corsEnableAnnotationKey] = "true"
 		annotations["nginx.ingress.kubernetes.io/cors-max-age"] = strconv.Itoa(maxAge)
 	}
-	// End CORS
+}
 
-	// Rate limits
+func (nginxAnnotations) rateLimits(annotations map[string]string, rateLimits *options.RateLimitOptions) {
 	if rps := rateLimits.RPS; rps != 0 {
 		annotations["nginx.ingress.kubernetes.io/limit-rps"] = fmt.Sprint(rps)
 
@@ -88,18 +169,138 @@ func (g *Generator) generateAnnotations(
 			annotations["nginx.ingress.kubernetes.io/limit-burst-multiplier"] = fmt.Sprint(burstMultiplier)
 		}
 	}
-	// End rate limits
+}
 
-	// Timeouts
+func (nginxAnnotations) timeouts(annotations map[string]string, timeoutOpts *options.TimeoutOptions) {
 	if requestTimeout := timeoutOpts.RequestTimeout; requestTimeout > 0 {
 		strTimeout := strconv.Itoa(int(requestTimeout) / 2)
 		if strTimeout == "0" {
 			strTimeout = "1"
 		}
-		annotations["nginx.ingress.kubernetes.io/proxy-send-timeout"] = strTimeout
-		annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"] = strTimeout
+		annotations[proxySendTimeoutAnnotationKey] = strTimeout
+		annotations[proxyReadTimeoutAnnotationKey] = strTimeout
 	}
-	// End Timeouts
+}
 
-	return annotations
+// generateSSLRedirectAnnotations adds the HTTPS redirect annotations to the given ones.
+// The redirect is enabled by default when the Ingress terminates TLS.
+func generateSSLRedirectAnnotations(annotations map[string]string, ingressOpts *options.IngressOptions) {
+	sslRedirect := ingressOpts.TLS.SecretName != ""
+	if ingressOpts.SSLRedirect != nil {
+		sslRedirect = *ingressOpts.SSLRedirect
+	}
+
+	// without TLS, there's nothing to redirect to unless explicitly enabled
+	if sslRedirect || ingressOpts.SSLRedirect != nil {
+		annotations[sslRedirectAnnotationKey] = strconv.FormatBool(sslRedirect)
+	}
+
+	if ingressOpts.ForceSSLRedirect {
+		annotations[forceSSLRedirectAnnotationKey] = "true"
+	}
+}
+
+// generateWebsocketAnnotations raises the proxy timeouts for websocket connections, which ingress-nginx upgrades
+// out of the box but closes once idle for longer than the timeouts. They take precedence over the request timeout.
+func generateWebsocketAnnotations(annotations map[string]string, ingressOpts *options.IngressOptions) {
+	if ingressOpts.Websocket == nil || !*ingressOpts.Websocket {
+		return
+	}
+
+	annotations[proxyReadTimeoutAnnotationKey] = websocketTimeout
+	annotations[proxySendTimeoutAnnotationKey] = websocketTimeout
+}
+
+func generateAffinityAnnotations(annotations map[string]string, affinityOpts *options.IngressAffinityOptions) {
+	if affinityOpts.Type == "" {
+		return
+	}
+
+	annotations[affinityAnnotationKey] = affinityOpts.Type
+	annotations[sessionCookieNameAnnotationKey] = affinityOpts.Cookie.Name
+
+	if affinityOpts.Cookie.Expires > 0 {
+		annotations[sessionCookieExpiresAnnotationKey] = strconv.Itoa(affinityOpts.Cookie.Expires)
+	}
+}
+
+// generateLimitExceptSnippet returns an NGINX configuration snippet denying requests with methods
+// other than the given ones. Note that allowing GET also allows HEAD.
+func generateLimitExceptSnippet(methods []string) string {
+	return fmt.Sprintf("limit_except %s {\n  deny all;\n}\n", strings.Join(methods, " "))
+}
+
+// generateHeadersAnnotations appends the directives setting the request and response headers
+// to the configuration snippet, after the limit_except block of ingress.restrict_methods if any
+func generateHeadersAnnotations(annotations map[string]string, ingressOpts *options.IngressOptions) {
+	if len(ingressOpts.RequestHeaders) == 0 && len(ingressOpts.ResponseHeaders) == 0 {
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString(annotations[configurationSnippetAnnotationKey])
+
+	for _, name := range sortedKeys(ingressOpts.RequestHeaders) {
+		fmt.Fprintf(&builder, "proxy_set_header %s %s;\n", name, quoteSnippetValue(ingressOpts.RequestHeaders[name]))
+	}
+
+	// more_set_headers, unlike add_header, also applies to error responses and overrides the upstream headers
+	for _, name := range sortedKeys(ingressOpts.ResponseHeaders) {
+		fmt.Fprintf(&builder, "more_set_headers %s;\n", quoteSnippetValue(name+": "+ingressOpts.ResponseHeaders[name]))
+	}
+
+	annotations[configurationSnippetAnnotationKey] = builder.String()
+}
+
+// generateSlashAnnotations appends the rewrite directive redirecting the requests to the canonical path,
+// with or without the trailing slash, to the configuration snippet.
+// The redirect is left out with a warning when the Ingress path doesn't also route the canonical path,
+// i.e. an Exact path or a regex anchored at its end, as it would make the path unreachable.
+func generateSlashAnnotations(annotations map[string]string, path string, pathType v1.PathType, ingressOpts *options.IngressOptions) {
+	var option, rewrite string
+	switch {
+	case ingressOpts.AppendSlash:
+		option, rewrite = "ingress.append_slash", "rewrite ^(.*[^/])$ $1/ permanent;\n"
+	case ingressOpts.StripSlash:
+		option, rewrite = "ingress.strip_slash", "rewrite ^(.+)/$ $1 permanent;\n"
+	default:
+		return
+	}
+
+	if pathType == pathTypeExact || strings.HasSuffix(path, "$") {
+		log.New(warnOutput, "WARN", log.Lmsgprefix).
+			Printf("%s is left out of the %s %s path, which doesn't route the redirected path", option, pathType, path)
+		return
+	}
+
+	annotations[configurationSnippetAnnotationKey] += rewrite
+}
+
+// quoteSnippetValue quotes the value as an NGINX string, variables such as $remote_addr still being expanded
+func quoteSnippetValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// generateLabels returns the labels of the generated resources, with the user-provided labels
+// taking precedence over the generated ones
+func generateLabels(ingressOpts *options.IngressOptions) map[string]string {
+	labels := map[string]string{
+		managedByLabelKey: managedByLabelValue,
+	}
+
+	for key, value := range ingressOpts.Labels {
+		labels[key] = value
+	}
+
+	return labels
 }
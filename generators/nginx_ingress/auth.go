@@ -0,0 +1,134 @@
+package nginx_ingress
+
+import (
+	"log"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	authTypeAnnotationKey   = "nginx.ingress.kubernetes.io/auth-type"
+	authSecretAnnotationKey = "nginx.ingress.kubernetes.io/auth-secret"
+	authRealmAnnotationKey  = "nginx.ingress.kubernetes.io/auth-realm"
+
+	authURLAnnotationKey             = "nginx.ingress.kubernetes.io/auth-url"
+	authSigninAnnotationKey          = "nginx.ingress.kubernetes.io/auth-signin"
+	authResponseHeadersAnnotationKey = "nginx.ingress.kubernetes.io/auth-response-headers"
+)
+
+// generateAuthAnnotations adds the annotations enabling the configured authentication to the given ones
+func generateAuthAnnotations(annotations map[string]string, authOpts *options.IngressAuthOptions) {
+	if basic := authOpts.Basic; basic.Secret != "" {
+		annotations[authTypeAnnotationKey] = "basic"
+		annotations[authSecretAnnotationKey] = basic.Secret
+
+		if basic.Realm != "" {
+			annotations[authRealmAnnotationKey] = basic.Realm
+		}
+	}
+
+	if authOpts.URL != "" {
+		annotations[authURLAnnotationKey] = authOpts.URL
+	}
+
+	if authOpts.Signin != "" {
+		annotations[authSigninAnnotationKey] = authOpts.Signin
+	}
+
+	if headers := authOpts.ResponseHeaders; len(headers) > 0 {
+		annotations[authResponseHeadersAnnotationKey] = strings.Join(headers, ",")
+	}
+}
+
+// pathAuthOpts returns the authentication options of the given path. With ingress.auth.from_spec, only the paths
+// with an operation having a security requirement are protected, by the configured authentication matching
+// its security schemes. Otherwise, all paths share the global authentication options.
+func pathAuthOpts(opts *options.Options, spec *openapi3.T, path string) options.IngressAuthOptions {
+	authOpts := opts.Ingress.Auth
+	if !authOpts.FromSpec {
+		return authOpts
+	}
+
+	pathItem := spec.Paths[path]
+	if pathItem == nil {
+		return options.IngressAuthOptions{}
+	}
+
+	// ingress-nginx can't protect the methods of a path separately, so the path is protected by the
+	// authentication of all its secured operations
+	schemes := map[string]bool{}
+	secured, unsecured := 0, 0
+	for _, method := range enabledMethods(opts, path, pathItem) {
+		operationSchemes := securitySchemes(spec, pathItem.GetOperation(method))
+		if len(operationSchemes) == 0 {
+			unsecured++
+			continue
+		}
+
+		secured++
+		for _, scheme := range operationSchemes {
+			schemes[authScheme(&authOpts, spec, scheme)] = true
+		}
+	}
+
+	if secured == 0 {
+		return options.IngressAuthOptions{}
+	}
+
+	if unsecured > 0 {
+		log.New(warnOutput, "WARN", log.Lmsgprefix).
+			Printf("only some operations of %s have a security requirement which ingress-nginx can't apply per method, the whole path is protected", path)
+	}
+
+	// keep the configured authentication matching the schemes, falling back to the only configured one
+	if !schemes[options.AuthSchemeBasic] && authOpts.URL != "" {
+		authOpts.Basic = options.IngressBasicAuthOptions{}
+	}
+
+	if !schemes[options.AuthSchemeForward] && authOpts.Basic.Secret != "" {
+		authOpts.URL = ""
+		authOpts.Signin = ""
+		authOpts.ResponseHeaders = nil
+	}
+
+	return authOpts
+}
+
+// securitySchemes returns the names of the security schemes required by the operation, which defaults to the
+// global security requirements. None are returned if the requirements allow anonymous access, i.e. contain {}.
+func securitySchemes(spec *openapi3.T, operation *openapi3.Operation) []string {
+	requirements := spec.Security
+	if operation.Security != nil {
+		requirements = *operation.Security
+	}
+
+	var schemes []string
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			return nil
+		}
+
+		for scheme := range requirement {
+			schemes = append(schemes, scheme)
+		}
+	}
+
+	return schemes
+}
+
+// authScheme returns the authentication, basic or forward, protecting the given security scheme
+func authScheme(authOpts *options.IngressAuthOptions, spec *openapi3.T, scheme string) string {
+	if authScheme, ok := authOpts.Schemes[scheme]; ok {
+		return authScheme
+	}
+
+	if ref, ok := spec.Components.SecuritySchemes[scheme]; ok && ref.Value != nil &&
+		ref.Value.Type == "http" && strings.EqualFold(ref.Value.Scheme, "basic") {
+		return options.AuthSchemeBasic
+	}
+
+	return options.AuthSchemeForward
+}
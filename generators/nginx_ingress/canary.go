@@ -0,0 +1,49 @@
+package nginx_ingress
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/networking/v1"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	canaryAnnotationKey       = "nginx.ingress.kubernetes.io/canary"
+	canaryWeightAnnotationKey = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+// newCanaryIngress returns a copy of the given Ingress routing the configured share of its traffic
+// to the canary Service. ingress-nginx pairs it with the main Ingress by host and path.
+// The canary Service port defaults to the target Service port, by number or name.
+func newCanaryIngress(ingress v1.Ingress, serviceOpts *options.ServiceOptions) v1.Ingress {
+	canary := &serviceOpts.Canary
+
+	port := v1.ServiceBackendPort{Number: canary.Port}
+	if canary.Port == 0 {
+		port.Name = serviceOpts.PortName
+	}
+
+	canaryIngress := *ingress.DeepCopy()
+	canaryIngress.Name = sanitizeResourceName(ingress.Name + "-canary")
+
+	if canaryIngress.Annotations == nil {
+		canaryIngress.Annotations = map[string]string{}
+	}
+	canaryIngress.Annotations[canaryAnnotationKey] = "true"
+	canaryIngress.Annotations[canaryWeightAnnotationKey] = strconv.Itoa(canary.Weight)
+
+	// the default backend stays with the main Ingress
+	canaryIngress.Spec.DefaultBackend = nil
+
+	for _, rule := range canaryIngress.Spec.Rules {
+		for i := range rule.HTTP.Paths {
+			rule.HTTP.Paths[i].Backend.Service = &v1.IngressServiceBackend{
+				Name: canary.Name,
+				Port: port,
+			}
+		}
+	}
+
+	return canaryIngress
+}
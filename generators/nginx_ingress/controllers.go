@@ -0,0 +1,235 @@
+package nginx_ingress
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	// nginxAnnotationPrefix prefixes the annotations only understood by ingress-nginx
+	nginxAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+
+	haproxyAnnotationPrefix = "haproxy-ingress.github.io/"
+
+	haproxyCORSEnableAnnotationKey    = haproxyAnnotationPrefix + "cors-enable"
+	haproxyTimeoutServerAnnotationKey = haproxyAnnotationPrefix + "timeout-server"
+
+	albAnnotationPrefix = "alb.ingress.kubernetes.io/"
+
+	albSchemeAnnotationKey      = albAnnotationPrefix + "scheme"
+	albTargetTypeAnnotationKey  = albAnnotationPrefix + "target-type"
+	albListenPortsAnnotationKey = albAnnotationPrefix + "listen-ports"
+	albGroupNameAnnotationKey   = albAnnotationPrefix + "group.name"
+)
+
+// haproxyAnnotations generates HAProxy Ingress annotations,
+// see https://haproxy-ingress.github.io/docs/configuration/keys/
+type haproxyAnnotations struct{}
+
+func (haproxyAnnotations) ingress(map[string]string) {}
+
+func (haproxyAnnotations) cors(annotations map[string]string, cors *options.CORSOptions) {
+	if origins := cors.Origins; len(origins) > 0 {
+		if len(origins) > 1 {
+			log.New(warnOutput, "WARN", log.Lmsgprefix).
+				Printf("HAProxy Ingress only supports a single origin. Choosing the first url: %s", origins[0])
+		}
+		annotations[haproxyCORSEnableAnnotationKey] = "true"
+		annotations[haproxyAnnotationPrefix+"cors-allow-origin"] = origins[0]
+	}
+
+	if methods := cors.Methods; len(methods) > 0 {
+		annotations[haproxyCORSEnableAnnotationKey] = "true"
+		annotations[haproxyAnnotationPrefix+"cors-allow-methods"] = strings.Join(methods, ", ")
+	}
+
+	if allowHeaders := cors.Headers; len(allowHeaders) > 0 {
+		annotations[haproxyCORSEnableAnnotationKey] = "true"
+		annotations[haproxyAnnotationPrefix+"cors-allow-headers"] = strings.Join(allowHeaders, ", ")
+	}
+
+	if exposeHeaders := cors.ExposeHeaders; len(exposeHeaders) > 0 {
+		annotations[haproxyCORSEnableAnnotationKey] = "true"
+		annotations[haproxyAnnotationPrefix+"cors-expose-headers"] = strings.Join(exposeHeaders, ", ")
+	}
+
+	// credentials are allowed by default, as with ingress-nginx
+	if allowCredentials := cors.Credentials; allowCredentials != nil && !*allowCredentials {
+		annotations[haproxyCORSEnableAnnotationKey] = "true"
+		annotations[haproxyAnnotationPrefix+"cors-allow-credentials"] = "false"
+	}
+
+	if maxAge := cors.MaxAge; maxAge > 0 {
+		annotations[haproxyCORSEnableAnnotationKey] = "true"
+		annotations[haproxyAnnotationPrefix+"cors-max-age"] = strconv.Itoa(maxAge)
+	}
+}
+
+// rateLimits limits the requests per second of each client. HAProxy Ingress has no burst setting.
+func (haproxyAnnotations) rateLimits(annotations map[string]string, rateLimits *options.RateLimitOptions) {
+	if rps := rateLimits.RPS; rps != 0 {
+		annotations[haproxyAnnotationPrefix+"limit-rps"] = fmt.Sprint(rps)
+	}
+}
+
+// timeouts sets the time to wait for the upstream Service to respond, HAProxy applying it to the whole response
+// rather than to each read and write as ingress-nginx does
+func (haproxyAnnotations) timeouts(annotations map[string]string, timeoutOpts *options.TimeoutOptions) {
+	if requestTimeout := timeoutOpts.RequestTimeout; requestTimeout > 0 {
+		annotations[haproxyTimeoutServerAnnotationKey] = fmt.Sprintf("%ds", requestTimeout)
+	}
+}
+
+// retries are left out with a warning, HAProxy Ingress only retrying the connections to the backend
+func (haproxyAnnotations) retries(_ map[string]string, retryOpts *options.RetryOptions) {
+	if retryOpts.Attempts > 0 {
+		warnUnsupported("HAProxy Ingress", "retries", "retry the requests on the clients instead")
+	}
+}
+
+// traefikAnnotations generates annotations for the Traefik Kubernetes Ingress provider,
+// which configures CORS, rate limits and timeouts with Middleware resources rather than annotations.
+// These options are left out with a warning, the traefik generator generating the Middlewares.
+type traefikAnnotations struct{}
+
+func (traefikAnnotations) ingress(map[string]string) {}
+
+func (traefikAnnotations) cors(_ map[string]string, cors *options.CORSOptions) {
+	if hasCORS(cors) {
+		warnTraefikUnsupported("cors")
+	}
+}
+
+func (traefikAnnotations) rateLimits(_ map[string]string, rateLimits *options.RateLimitOptions) {
+	if rateLimits.RPS != 0 {
+		warnTraefikUnsupported("rate_limits")
+	}
+}
+
+func (traefikAnnotations) timeouts(_ map[string]string, timeoutOpts *options.TimeoutOptions) {
+	if timeoutOpts.RequestTimeout > 0 {
+		warnTraefikUnsupported("timeouts")
+	}
+}
+
+func (traefikAnnotations) retries(_ map[string]string, retryOpts *options.RetryOptions) {
+	if retryOpts.Attempts > 0 {
+		warnTraefikUnsupported("retries")
+	}
+}
+
+func warnTraefikUnsupported(option string) {
+	warnUnsupported("Traefik", option, "use the traefik generator to generate Middlewares instead")
+}
+
+// albAnnotations generates annotations for the AWS Load Balancer Controller from the alb options,
+// see https://kubernetes-sigs.github.io/aws-load-balancer-controller/latest/guide/ingress/annotations/.
+// Application Load Balancers have no CORS, rate limit or request timeout annotations, these options are left out with a warning.
+type albAnnotations struct {
+	albOpts *options.ALBOptions
+}
+
+func (a albAnnotations) ingress(annotations map[string]string) {
+	if scheme := a.albOpts.Scheme; scheme != "" {
+		annotations[albSchemeAnnotationKey] = scheme
+	}
+
+	if targetType := a.albOpts.TargetType; targetType != "" {
+		annotations[albTargetTypeAnnotationKey] = targetType
+	}
+
+	if listenPorts := a.albOpts.ListenPorts; len(listenPorts) > 0 {
+		// the listen ports are a JSON list of protocol to port objects, e.g. [{"HTTP":80},{"HTTPS":443}]
+		ports := make([]map[string]int, 0, len(listenPorts))
+		for _, listenPort := range listenPorts {
+			// the listen ports have already been validated
+			protocol, port, _ := options.ParseALBListenPort(listenPort)
+			ports = append(ports, map[string]int{protocol: port})
+		}
+
+		b, _ := json.Marshal(ports)
+		annotations[albListenPortsAnnotationKey] = string(b)
+	}
+
+	if groupName := a.albOpts.GroupName; groupName != "" {
+		annotations[albGroupNameAnnotationKey] = groupName
+	}
+}
+
+func (albAnnotations) cors(_ map[string]string, cors *options.CORSOptions) {
+	if hasCORS(cors) {
+		warnALBUnsupported("cors")
+	}
+}
+
+func (albAnnotations) rateLimits(_ map[string]string, rateLimits *options.RateLimitOptions) {
+	if rateLimits.RPS != 0 {
+		warnALBUnsupported("rate_limits")
+	}
+}
+
+func (albAnnotations) timeouts(_ map[string]string, timeoutOpts *options.TimeoutOptions) {
+	if timeoutOpts.RequestTimeout > 0 {
+		warnALBUnsupported("timeouts")
+	}
+}
+
+func (albAnnotations) retries(_ map[string]string, retryOpts *options.RetryOptions) {
+	if retryOpts.Attempts > 0 {
+		warnUnsupported("The AWS Load Balancer Controller", "retries", "retry the requests on the clients instead")
+	}
+}
+
+func warnALBUnsupported(option string) {
+	warnUnsupported("The AWS Load Balancer Controller", option, "configure them on the application or with AWS WAF instead")
+}
+
+// hasCORS returns whether any of the CORS options is set
+func hasCORS(cors *options.CORSOptions) bool {
+	return len(cors.Origins) > 0 || len(cors.Methods) > 0 || len(cors.Headers) > 0 || len(cors.ExposeHeaders) > 0 ||
+		cors.Credentials != nil || cors.MaxAge > 0
+}
+
+// controllerNames are the names of the ingress controllers not understanding the ingress-nginx annotations
+var controllerNames = map[string]string{
+	options.IngressControllerHAProxy: "HAProxy Ingress",
+	options.IngressControllerTraefik: "Traefik",
+	options.IngressControllerALB:     "The AWS Load Balancer Controller",
+}
+
+// dropNGINXAnnotations leaves out, with a warning, the ingress-nginx annotations generated for the options
+// which have no equivalent in the vocabulary of another ingress.controller, e.g. the rewrites, the configuration
+// snippets of the headers or the authentication. The user-provided ingress.annotations are kept as is.
+func dropNGINXAnnotations(annotations map[string]string, controller, ingressName string) {
+	controllerName, ok := controllerNames[controller]
+	if !ok {
+		return
+	}
+
+	var dropped []string
+	for key := range annotations {
+		if strings.HasPrefix(key, nginxAnnotationPrefix) {
+			dropped = append(dropped, key)
+			delete(annotations, key)
+		}
+	}
+
+	if len(dropped) == 0 {
+		return
+	}
+
+	sort.Strings(dropped)
+	log.New(warnOutput, "WARN", log.Lmsgprefix).
+		Printf("%s doesn't support the ingress-nginx %s annotations, they are left out of the %s Ingress", controllerName, strings.Join(dropped, ", "), ingressName)
+}
+
+func warnUnsupported(controller, option, hint string) {
+	log.New(warnOutput, "WARN", log.Lmsgprefix).
+		Printf("%s doesn't support %s through Ingress annotations, %s", controller, option, hint)
+}
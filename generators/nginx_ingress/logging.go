@@ -0,0 +1,22 @@
+package nginx_ingress
+
+import (
+	"github.com/kubeshop/kusk/options"
+)
+
+// the decisions taken for each spec path, logged at verbosity level 1
+const (
+	// decisionIncluded is logged for a path routed by an Ingress of its own, or the single one
+	decisionIncluded = "included"
+	// decisionDisabled is logged for a path disabled with its x-kusk extension, globally, or as a health path
+	decisionDisabled = "disabled"
+	// decisionExcluded is logged for a path filtered out with path.include, path.exclude or path.methods
+	decisionExcluded = "excluded"
+	// decisionMerged is logged for a path routed by the Ingress of another path, e.g. with ingress.merge_static
+	decisionMerged = "merged"
+)
+
+// logPathDecision logs the decision taken for the spec path, along with the given key and value pairs
+func logPathDecision(opts *options.Options, path, decision string, keysAndValues ...interface{}) {
+	opts.GetLogger().V(1).Info("path decision", append([]interface{}{"path", path, "decision", decision}, keysAndValues...)...)
+}
@@ -0,0 +1,102 @@
+package nginx_ingress
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	deploymentAPIVersion = "apps/v1"
+	deploymentKind       = "Deployment"
+
+	defaultMockImage = "hashicorp/http-echo"
+	defaultMockPort  = 5678
+
+	// mockAppLabel selects the mock pods, matching the default NetworkPolicy pod selector
+	mockAppLabel = "app"
+)
+
+// newMock returns the mock upstream Service of the generated Ingresses, named and exposing the port they route to,
+// along with the Deployment of the echo server behind it
+func (g *Generator) newMock(opts *options.Options) (appsv1.Deployment, corev1.Service) {
+	port := opts.Mock.Port
+	if port == 0 {
+		port = defaultMockPort
+	}
+
+	image := opts.Mock.Image
+	var args []string
+	if image == "" {
+		image = defaultMockImage
+		// answer each request with the Service name, to tell which Service a route leads to
+		args = []string{fmt.Sprintf("-listen=:%d", port), "-text=" + opts.Service.Name}
+	}
+
+	selector := map[string]string{mockAppLabel: opts.Service.Name}
+
+	podLabels := generateLabels(&opts.Ingress)
+	for key, value := range selector {
+		podLabels[key] = value
+	}
+
+	replicas := int32(1)
+
+	deployment := appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: deploymentAPIVersion,
+			Kind:       deploymentKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Service.Name,
+			Namespace: opts.Service.Namespace,
+			Labels:    generateLabels(&opts.Ingress),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "mock",
+							Image: image,
+							Args:  args,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "http",
+									ContainerPort: port,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	service := g.newService(opts)
+	service.Spec.Selector = selector
+	service.Spec.Ports[0].TargetPort = intstr.FromString("http")
+
+	return deployment, service
+}
+
+func (g *Generator) buildMockOutput(opts *options.Options) (string, error) {
+	deployment, service := g.newMock(opts)
+
+	return generators.MarshalResources([]runtime.Object{&deployment, &service})
+}
@@ -0,0 +1,90 @@
+package nginx_ingress
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	networkPolicyAPIVersion = "networking.k8s.io/v1"
+	networkPolicyKind       = "NetworkPolicy"
+
+	defaultControllerNamespace = "ingress-nginx"
+
+	// namespaceNameLabel is set by Kubernetes on each namespace to its name
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+// newNetworkPolicy allows traffic from the ingress controller namespace to the target Service pods on the Service port.
+// The policy is created in the Service namespace as it only applies to pods in its own namespace.
+func (g *Generator) newNetworkPolicy(opts *options.Options) v1.NetworkPolicy {
+	podSelector := opts.NetworkPolicy.PodSelector
+	if len(podSelector) == 0 {
+		podSelector = map[string]string{"app": opts.Service.Name}
+	}
+
+	controllerNamespace := opts.NetworkPolicy.ControllerNamespace
+	if controllerNamespace == "" {
+		controllerNamespace = defaultControllerNamespace
+	}
+
+	protocol := corev1.ProtocolTCP
+	port := intstr.FromInt(int(opts.Service.Port))
+	if opts.Service.PortName != "" {
+		// expected to be the name of the container port as well
+		port = intstr.FromString(opts.Service.PortName)
+	}
+
+	return v1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkPolicyAPIVersion,
+			Kind:       networkPolicyKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sanitizeResourceName(opts.NamePrefix + opts.Service.Name + "-ingress"),
+			Namespace: opts.Service.Namespace,
+			Labels:    generateLabels(&opts.Ingress),
+		},
+		Spec: v1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: podSelector,
+			},
+			PolicyTypes: []v1.PolicyType{v1.PolicyTypeIngress},
+			Ingress: []v1.NetworkPolicyIngressRule{
+				{
+					From: []v1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{namespaceNameLabel: controllerNamespace},
+							},
+						},
+					},
+					Ports: []v1.NetworkPolicyPort{
+						{
+							Protocol: &protocol,
+							Port:     &port,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (g *Generator) buildNetworkPolicyOutput(opts *options.Options) (string, error) {
+	networkPolicy := g.newNetworkPolicy(opts)
+
+	b, err := generators.Marshal(networkPolicy)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal network policy resource: %+v: %s", networkPolicy, err.Error())
+	}
+
+	return "---\n" + string(b), nil
+}
@@ -2,215 +2,1253 @@ package nginx_ingress
 
 import (
 	"fmt"
+	"hash/fnv"
+	"io"
 	"log"
 	"os"
 	"reflect"
 	"regexp"
 	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/ghodss/yaml"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kubeshop/kusk/generators"
 	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
 )
 
 const (
 	ingressAPIVersion = "networking.k8s.io/v1"
 	ingressKind       = "Ingress"
+
+	// maxResourceNameLength is the maximum length of an RFC 1123 label
+	maxResourceNameLength = 63
+
+	defaultNameTemplate      = "{{.Service}}-ingress"
+	defaultSplitNameTemplate = "{{.Service}}-{{.Path}}"
+
+	// defaultVariableRegex matches any path segment, e.g. UUIDs or slugs
+	defaultVariableRegex = "[^/]+"
 )
 
-var (
-	ingressClassName = "nginx"
-	pathTypePrefix   = v1.PathTypePrefix
-	pathTypeExact    = v1.PathTypeExact
+var (
+	defaultIngressClassName        = "nginx"
+	pathTypePrefix                 = v1.PathTypePrefix
+	pathTypeExact                  = v1.PathTypeExact
+	pathTypeImplementationSpecific = v1.PathTypeImplementationSpecific
+
+	openApiPathVariableRegex = regexp.MustCompile(`{[A-Za-z_][A-Za-z0-9_]*}`)
+
+	invalidResourceNameCharsRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+	repeatedSlashesRegex = regexp.MustCompile(`/{2,}`)
+
+	// warnOutput is where warnings are logged to
+	warnOutput io.Writer = os.Stderr
+)
+
+func init() {
+	generators.Registry["ingress-nginx"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "ingress-nginx"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("ingress-nginx", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"",
+		"a base path for Service endpoints, defaults to the path of the spec servers URLs or /",
+	)
+
+	fs.String(
+		"path.trim_prefix",
+		"",
+		"a prefix to trim from the URL before forwarding to the upstream Service",
+	)
+
+	fs.Bool(
+		"path.split",
+		false,
+		"force Kusk to generate a separate Ingress for each operation",
+	)
+
+	fs.String(
+		"path.variable_regex",
+		"",
+		"the regular expression matching the value of a path variable in the regex paths, defaults to [^/]+",
+	)
+
+	fs.Bool(
+		"path.default",
+		false,
+		"generate a single prefix rule at path.base when the spec has no paths, e.g. to proxy a service whose spec only declares servers",
+	)
+
+	fs.Bool(
+		"allow-empty",
+		false,
+		"succeed without generating anything when no path is left to route, e.g. when all of them are disabled",
+	)
+	fs.SetAnnotation("allow-empty", generators.OptionKeyAnnotation, []string{"allow_empty"})
+
+	fs.String(
+		"host",
+		"",
+		"an Ingress Host to listen on",
+	)
+
+	fs.Uint32(
+		"rate_limits.rps",
+		0,
+		"request per second rate limit",
+	)
+
+	fs.Uint32(
+		"rate_limits.burst",
+		0,
+		"request per second burst",
+	)
+
+	fs.Uint32(
+		"timeouts.request_timeout",
+		0,
+		"total request timeout (seconds)",
+	)
+
+	fs.Int(
+		"retries.attempts",
+		0,
+		"the number of times a failed request is retried",
+	)
+
+	fs.Uint32(
+		"retries.per_try_timeout",
+		0,
+		"the timeout of each attempt (seconds), limits the total time spent on all attempts",
+	)
+
+	fs.Bool(
+		"retries.force_all",
+		false,
+		"retry the requests of all the methods, including the non-idempotent ones such as POST which aren't retried otherwise",
+	)
+
+	fs.StringSlice(
+		"cors.origins",
+		[]string{},
+		"a comma-separated list of origins allowed to access the API",
+	)
+
+	fs.StringSlice(
+		"cors.methods",
+		[]string{},
+		"a comma-separated list of methods allowed for CORS requests",
+	)
+
+	fs.StringSlice(
+		"cors.headers",
+		[]string{},
+		"a comma-separated list of headers allowed for CORS requests",
+	)
+
+	fs.StringSlice(
+		"cors.expose_headers",
+		[]string{},
+		"a comma-separated list of headers exposed to the browser",
+	)
+
+	fs.Bool(
+		"cors.credentials",
+		false,
+		"whether credentials are allowed for CORS requests",
+	)
+
+	fs.Int(
+		"cors.max_age",
+		0,
+		"how long (seconds) the results of a preflight request can be cached",
+	)
+
+	fs.Bool(
+		"cors.grpc_web",
+		false,
+		"fill the CORS methods, headers, exposed headers and max age left unset with the ones gRPC-Web clients need",
+	)
+
+	fs.StringToString(
+		"ingress.annotations",
+		map[string]string{},
+		"additional Ingress annotations in the form of key=value, can be repeated",
+	)
+
+	fs.String(
+		"ingress.api_version",
+		"",
+		"the API version of the generated Ingresses, networking.k8s.io/v1 (default) or networking.k8s.io/v1beta1 for clusters older than Kubernetes 1.19",
+	)
+
+	fs.StringToString(
+		"ingress.labels",
+		map[string]string{},
+		"additional labels of all generated resources in the form of key=value, can be repeated",
+	)
+
+	fs.String(
+		"ingress.path_type",
+		"",
+		"force a path type for generated Ingress paths: Exact, Prefix or ImplementationSpecific",
+	)
+
+	fs.Bool(
+		"ingress.restrict_methods",
+		false,
+		"limit each path to the HTTP methods defined for it in the spec, only applies with path.split",
+	)
+
+	fs.String(
+		"ingress.tls.secret_name",
+		"",
+		"a name of the Secret containing TLS certificate for the Ingress",
+	)
+
+	fs.StringSlice(
+		"ingress.tls.hosts",
+		[]string{},
+		"a comma-separated list of hosts included in the TLS certificate, defaults to the Ingress host",
+	)
+
+	fs.Bool(
+		"ingress.ssl_redirect",
+		false,
+		"redirect HTTP requests to HTTPS, defaults to true when ingress.tls.secret_name is set",
+	)
+
+	fs.String(
+		"ingress.class",
+		"",
+		"the IngressClass of the generated Ingresses, defaults to nginx",
+	)
+
+	fs.Bool(
+		"ingress.force_ssl_redirect",
+		false,
+		"redirect HTTP requests to HTTPS even without TLS configured on the Ingress",
+	)
+
+	fs.String(
+		"ingress.proxy_body_size",
+		"",
+		"the maximum allowed size of the request body, e.g. 8m, or 0 to disable the limit",
+	)
+
+	fs.StringSlice(
+		"ingress.whitelist_source_range",
+		[]string{},
+		"a comma-separated list of CIDRs the client IP has to be in to be allowed, e.g. 10.0.0.0/24",
+	)
+
+	fs.Bool(
+		"ingress.websocket",
+		false,
+		"raise the proxy timeouts to keep idle websocket connections open",
+	)
+
+	fs.String(
+		"ingress.controller",
+		"",
+		"the ingress controller to generate the CORS, rate limit, timeout and retry annotations for: nginx, haproxy, traefik or alb, defaults to nginx",
+	)
+
+	fs.String(
+		"alb.scheme",
+		"",
+		"whether the AWS load balancer is internet-facing or internal, with ingress.controller alb",
+	)
+
+	fs.String(
+		"alb.target_type",
+		"",
+		"how the AWS load balancer routes to the pods: instance or ip, with ingress.controller alb",
+	)
+
+	fs.StringSlice(
+		"alb.listen_ports",
+		[]string{},
+		"a comma-separated list of protocol:port the AWS load balancer listens on, e.g. HTTP:80,HTTPS:443, with ingress.controller alb",
+	)
+
+	fs.String(
+		"alb.group_name",
+		"",
+		"the IngressGroup sharing an AWS load balancer to add the Ingresses to, with ingress.controller alb",
+	)
+
+	fs.String(
+		"ingress.proxy_buffering",
+		"",
+		"turn the buffering of the upstream responses on or off, e.g. off for server-sent events",
+	)
+
+	fs.StringToString(
+		"ingress.request_headers",
+		map[string]string{},
+		"headers to set on the requests proxied to the Service in the form of name=value, can be repeated",
+	)
+
+	fs.StringToString(
+		"ingress.response_headers",
+		map[string]string{},
+		"headers to set on the responses in the form of name=value, can be repeated",
+	)
+
+	fs.Bool(
+		"ingress.append_slash",
+		false,
+		"permanently redirect the requests to a path without a trailing slash to the same path with one",
+	)
+
+	fs.Bool(
+		"ingress.strip_slash",
+		false,
+		"permanently redirect the requests to a path with a trailing slash to the same path without it",
+	)
+
+	fs.String(
+		"ingress.name_template",
+		"",
+		"a Go template for the Ingress names with access to .Service, .Path, .Namespace and .Host, defaults to {{.Service}}-ingress or {{.Service}}-{{.Path}} with path.split",
+	)
+
+	fs.Bool(
+		"ingress.default_backend",
+		false,
+		"set the target Service as the default backend of the generated Ingress resources",
+	)
+
+	fs.Bool(
+		"ingress.merge_static",
+		false,
+		"generate a single Ingress for the static paths which need no rewrite, only applies with path.split",
+	)
+
+	fs.Bool(
+		"ingress.dedupe_names",
+		false,
+		"suffix the names of Ingresses of different paths which would have the same name with a counter instead of failing, only applies with path.split",
+	)
+
+	fs.String(
+		"ingress.auth.basic.secret",
+		"",
+		"a name of the Secret containing htpasswd credentials to enable basic authentication",
+	)
+
+	fs.String(
+		"ingress.auth.basic.realm",
+		"",
+		"a message displayed in the basic authentication prompt, requires ingress.auth.basic.secret",
+	)
+
+	fs.String(
+		"ingress.auth.url",
+		"",
+		"a URL of an external authentication service to forward requests to before proxying them",
+	)
+
+	fs.String(
+		"ingress.auth.signin",
+		"",
+		"a URL to redirect unauthenticated requests to",
+	)
+
+	fs.StringSlice(
+		"ingress.auth.response_headers",
+		[]string{},
+		"a comma-separated list of authentication response headers to pass to the upstream Service",
+	)
+
+	fs.Bool(
+		"ingress.auth.from_spec",
+		false,
+		"only protect the paths with a security requirement in the spec, with the authentication matching their security schemes",
+	)
+
+	fs.StringToString(
+		"ingress.auth.schemes",
+		map[string]string{},
+		"the authentication, basic or forward, of a spec security scheme in the form of scheme=basic, can be repeated",
+	)
+
+	fs.String(
+		"ingress.affinity",
+		"",
+		"a session affinity type to route the requests of a client to the same endpoint, only cookie is supported",
+	)
+	// ingress.affinity.cookie.* flags are nested under the flag name, so it sets the type option instead
+	fs.SetAnnotation("ingress.affinity", generators.OptionKeyAnnotation, []string{"ingress.affinity.type"})
+
+	fs.String(
+		"ingress.affinity.cookie.name",
+		"",
+		"a name of the session affinity cookie, required when ingress.affinity is cookie",
+	)
+
+	fs.Int(
+		"ingress.affinity.cookie.expires",
+		0,
+		"a lifetime of the session affinity cookie in seconds, lasts for the browser session if not set",
+	)
+
+	fs.String(
+		"service.port_name",
+		"",
+		"reference the target Service port by name instead of service.port",
+	)
+
+	fs.String(
+		"service.protocol",
+		"",
+		"the protocol spoken by the target Service, one of HTTP, HTTPS, GRPC or GRPCS, defaults to HTTP",
+	)
+
+	fs.StringSlice(
+		"path.methods",
+		[]string{},
+		"an HTTP method, e.g. GET, restricting generation to the paths with an operation with one of them, can be repeated",
+	)
+
+	fs.Bool(
+		"grpc",
+		false,
+		"serve the paths with gRPC, setting the backend protocol to GRPC unless service.protocol is GRPCS",
+	)
+
+	fs.String(
+		"service.canary.name",
+		"",
+		"a canary Service to route a share of the traffic to",
+	)
+
+	fs.Int32(
+		"service.canary.port",
+		0,
+		"the canary Service port, defaults to the target Service port",
+	)
+
+	fs.Int(
+		"service.canary.weight",
+		0,
+		"the percentage of requests to route to the canary Service, from 0 to 100",
+	)
+
+	fs.Bool(
+		"generate-service",
+		false,
+		"additionally generate the target Service, of type ClusterIP, for when it doesn't exist yet",
+	)
+	fs.SetAnnotation("generate-service", generators.OptionKeyAnnotation, []string{"service.generate"})
+
+	fs.StringToString(
+		"service.selector",
+		map[string]string{},
+		"labels selecting the pods of the generated Service in the form of key=value, can be repeated, required by generate-service",
+	)
+
+	fs.Bool(
+		"generate-mock",
+		false,
+		"additionally generate a mock target Service, along with a Deployment running an echo server, to test the routing without the actual backend",
+	)
+	fs.SetAnnotation("generate-mock", generators.OptionKeyAnnotation, []string{"mock.generate"})
+
+	fs.String(
+		"mock.image",
+		"",
+		"the image of the mock server, defaults to hashicorp/http-echo answering each request with the Service name",
+	)
+
+	fs.Int32(
+		"mock.port",
+		0,
+		"the port the mock server listens on, defaults to 5678",
+	)
+
+	fs.String(
+		"health.internal_class",
+		"",
+		"the Ingress class to route the paths tagged with health: true to, instead of leaving them out",
+	)
+
+	fs.Bool(
+		"generate-network-policy",
+		false,
+		"additionally generate a NetworkPolicy allowing traffic from the ingress controller to the target Service pods",
+	)
+	fs.SetAnnotation("generate-network-policy", generators.OptionKeyAnnotation, []string{"network_policy.generate"})
+
+	fs.StringToString(
+		"network-policy.pod-selector",
+		map[string]string{},
+		"labels selecting the target Service pods in the form of key=value, can be repeated, defaults to app=<service name>",
+	)
+	fs.SetAnnotation("network-policy.pod-selector", generators.OptionKeyAnnotation, []string{"network_policy.pod_selector"})
+
+	fs.String(
+		"network_policy.controller_namespace",
+		"",
+		"the namespace of the ingress controller, defaults to ingress-nginx",
+	)
+
+	fs.String(
+		"nginx_ingress.rewrite_target",
+		"",
+		"a custom NGINX rewrite target",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates ingress-nginx resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	resources, err := g.GenerateResources(opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	return generators.MarshalResources(resources)
+}
+
+// GenerateResources returns the generated Ingress resources, followed by the Service, the mock Deployment and Service,
+// and the NetworkPolicy if enabled
+func (g *Generator) GenerateResources(opts *options.Options, spec *openapi3.T) ([]runtime.Object, error) {
+	ingresses, err := g.generateIngresses(opts, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := ingressObjects(&opts.Ingress, ingresses)
+
+	if len(ingresses) > 0 && opts.Service.Generate {
+		service := g.newService(opts)
+		resources = append(resources, &service)
+	}
+
+	if len(ingresses) > 0 && opts.Mock.Generate {
+		deployment, service := g.newMock(opts)
+		resources = append(resources, &deployment, &service)
+	}
+
+	if len(ingresses) > 0 && opts.NetworkPolicy.Generate {
+		networkPolicy := g.newNetworkPolicy(opts)
+		resources = append(resources, &networkPolicy)
+	}
+
+	return resources, nil
+}
+
+// GenerateFiles generates the same resources as Generate, each Ingress into a separate file named after it.
+func (g *Generator) GenerateFiles(opts *options.Options, spec *openapi3.T) (generators.Files, error) {
+	ingresses, err := g.generateIngresses(opts, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := buildFiles(ingressObjects(&opts.Ingress, ingresses))
+	if err != nil || len(ingresses) == 0 {
+		return files, err
+	}
+
+	if opts.Service.Generate {
+		service, err := g.buildServiceOutput(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := files.Add(opts.Service.Name+"-service", service); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Mock.Generate {
+		mock, err := g.buildMockOutput(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := files.Add(opts.Service.Name+"-mock", mock); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.NetworkPolicy.Generate {
+		return files, nil
+	}
+
+	networkPolicy, err := g.buildNetworkPolicyOutput(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := files.Add(opts.Service.Name+"-network-policy", networkPolicy); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ListPaths lists the included spec paths along with the path, path type and name of the Ingress generated for each
+func (g *Generator) ListPaths(opts *options.Options, spec *openapi3.T) ([]generators.Path, error) {
+	_, paths, err := g.generateRoutes(opts, spec)
+	return paths, err
+}
+
+func (g *Generator) generateIngresses(opts *options.Options, spec *openapi3.T) ([]v1.Ingress, error) {
+	ingresses, paths, err := g.generateRoutes(opts, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.checkNotEmpty(opts, spec, paths); err != nil {
+		return nil, err
+	}
+
+	return ingresses, nil
+}
+
+// generateRoutes generates the Ingresses along with the route of each included spec path
+func (g *Generator) generateRoutes(opts *options.Options, spec *openapi3.T) ([]v1.Ingress, []generators.Path, error) {
+	if opts.Path.Base == "" {
+		opts.Path.Base = g.serverBasePath(spec)
+	}
+
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return nil, nil, fmt.Errorf("failed to validate opts: %w", err)
+	}
+
+	var ingresses []v1.Ingress
+	var paths []generators.Path
+
+	// without paths to route, path.default routes all the requests under path.base
+	defaultRoute := len(spec.Paths) == 0 && opts.Path.Default
+
+	if !defaultRoute && g.shouldSplit(opts, spec) {
+		var err error
+		if ingresses, paths, err = g.splitPathRoutes(opts, spec); err != nil {
+			return nil, nil, err
+		}
+	} else if !opts.Disabled && (len(spec.Paths) > 0 || defaultRoute) {
+		if opts.Ingress.RestrictMethods {
+			log.New(warnOutput, "WARN", log.Lmsgprefix).
+				Printf("ingress.restrict_methods only applies when an Ingress is generated for each path, use path.split to enable it")
+		}
+
+		hosts := g.hosts(opts, spec)
+		name, err := g.resourceName(opts, defaultNameTemplate, "", hosts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		serviceOpts := &opts.Service
+		if opts.GRPC {
+			serviceOpts = grpcServiceOpts(opts.Service)
+		}
+
+		path := g.generatePath(&opts.Path, &opts.NGINXIngress)
+		pathType := g.pathType(&opts.Path, &opts.Ingress, pathTypePrefix)
+
+		retryOpts := idempotentRetryOpts(opts, spec, opts.Retries, kuskSpec.SortedPaths(spec.Paths)...)
+
+		ingress := g.newIngressResource(
+			name,
+			opts.Namespace,
+			path,
+			pathType,
+			g.generateAnnotations(
+				controllerAnnotationsFor(opts),
+				&opts.Path,
+				&opts.NGINXIngress,
+				&opts.CORS,
+				&opts.RateLimits,
+				&opts.Timeouts,
+				&retryOpts,
+			),
+			serviceOpts,
+			hosts,
+			&opts.Ingress,
+		)
+
+		ingresses = append(ingresses, ingress)
+
+		// the single Ingress routes all the paths, none of them being left out as it would have forced a split
+		for _, specPath := range kuskSpec.SortedPaths(spec.Paths) {
+			paths = append(paths, generators.Path{
+				SpecPath: specPath,
+				Path:     path,
+				PathType: string(pathType),
+				Name:     name,
+			})
+
+			logPathDecision(opts, specPath, decisionIncluded, "ingress", name)
+		}
+
+		if defaultRoute {
+			paths = append(paths, generators.Path{
+				Path:     path,
+				PathType: string(pathType),
+				Name:     name,
+			})
+		}
+	}
+
+	if opts.Service.Canary.Name != "" {
+		// the canary Ingresses are paired with the main ones by ingress-nginx annotations
+		if controllerName, ok := controllerNames[opts.Ingress.Controller]; ok {
+			warnUnsupported(controllerName, "service.canary", "split the traffic between the Services with its own resources instead")
+		} else {
+			for _, ingress := range ingresses {
+				ingresses = append(ingresses, newCanaryIngress(ingress, &opts.Service))
+			}
+		}
+	}
+
+	// We need to sort the ingresses as in the process of conversion of YAML to JSON
+	// the Go map's access mechanics randomize the order and therefore the output is shuffled.
+	// Not only it makes tests fail, it would also affect people who would use this in order to
+	// generate manifests and use them in GitOps processes
+	sort.Slice(ingresses, func(i, j int) bool {
+		return ingresses[i].Name < ingresses[j].Name
+	})
+
+	return ingresses, paths, nil
+}
+
+// checkNotEmpty returns an error if no path is left to route, unless allow_empty is set
+func (g *Generator) checkNotEmpty(opts *options.Options, spec *openapi3.T, paths []generators.Path) error {
+	if len(paths) > 0 || opts.AllowEmpty {
+		return nil
+	}
+
+	if len(spec.Paths) == 0 {
+		return fmt.Errorf("the spec has no paths to generate Ingress rules for, use --path.default to route all the requests under path.base or --allow-empty to allow it")
+	}
+
+	return fmt.Errorf("all the spec paths are disabled or filtered out, use --allow-empty to allow it")
+}
+
+// splitPath generates a separate Ingress for each enabled path
+func (g *Generator) splitPath(opts *options.Options, spec *openapi3.T) ([]v1.Ingress, error) {
+	ingresses, _, err := g.splitPathRoutes(opts, spec)
+	return ingresses, err
+}
+
+// splitPathRoutes generates a separate Ingress for each enabled path, along with the route of each spec path
+func (g *Generator) splitPathRoutes(opts *options.Options, spec *openapi3.T) ([]v1.Ingress, []generators.Path, error) {
+	ingresses := make([]v1.Ingress, 0)
+	hosts := g.hosts(opts, spec)
+
+	// the paths of each Ingress, to report name collisions
+	var ingressPaths []string
+
+	// the route of each spec path, along with the index of its Ingress to name it once the names are deduplicated,
+	// or -1 for the merged static paths
+	var routes []generators.Path
+	var routeIngresses []int
+	addRoutes := func(specPaths []string, path string, pathType v1.PathType, ingress int) {
+		for _, specPath := range specPaths {
+			routes = append(routes, generators.Path{SpecPath: specPath, Path: path, PathType: string(pathType)})
+			routeIngresses = append(routeIngresses, ingress)
+		}
+	}
+
+	// the Ingress of the static paths with ingress.merge_static
+	var merged *v1.Ingress
+	var mergedAnnotations map[string]string
+
+	paths, collapsed := collapseQueryPaths(opts, spec)
+
+	for _, path := range paths {
+		pathItem := spec.Paths[path]
+
+		// the path routed by the Ingress, leaving out the query string Ingress can't route on
+		routedPath := stripQueryString(path)
+
+		name, err := g.resourceName(opts, defaultSplitNameTemplate, ingressResourceNameFromPath(routedPath), hosts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pathOpts := opts.GetPathOpts(path, "")
+		corsOpts := opts.GetCORSOpts(path, "")
+		rateLimitOpts := g.pathRateLimitOpts(opts, path, pathItem)
+		timeoutOpts := opts.GetTimeoutOpts(path, "")
+		retryOpts := idempotentRetryOpts(opts, spec, opts.GetRetryOpts(path, ""), collapsed[path]...)
+		ingressOpts := opts.GetIngressOpts(path, "")
+		ingressOpts.Auth = pathAuthOpts(opts, spec, path)
+
+		// Get initial set of annotation based on current options
+		// will be modified next based on current path
+		annotations := g.generateAnnotations(
+			controllerAnnotationsFor(opts),
+			&pathOpts,
+			&opts.NGINXIngress,
+			&corsOpts,
+			&rateLimitOpts,
+			&timeoutOpts,
+			&retryOpts,
+		)
+
+		// if path has a parameter, replace {param} with a capture group of path.variable_regex and set use regex annotation to true
+		// if path has no parameter, just use path
+		var pathField string
+		pathType := pathTypeExact
+		if openApiPathVariableRegex.MatchString(routedPath) {
+			// regex matching is ingress controller specific
+			pathType = pathTypeImplementationSpecific
+			pathField = pathOpts.Base + openApiPathVariableRegex.ReplaceAllLiteralString(routedPath, variableCaptureGroup(opts))
+
+			// reference each capture group positionally. Given a path /orgs/{orgId}/users/{userId}, will return /orgs/$1/users/$2
+			rewrite := pathOpts.Base + positionalPathVariables(routedPath)
+			annotations[rewriteTargetAnnotationKey] = rewrite
+			annotations[useRegexAnnotationKey] = "true"
+		} else if routedPath == "/" {
+			pathField = rootPath(pathOpts.Base) + "$"
+			annotations[rewriteTargetAnnotationKey] = pathOpts.Base + "/"
+			annotations[useRegexAnnotationKey] = "true"
+		} else {
+			pathField = pathOpts.Base + routedPath
+			annotations[rewriteTargetAnnotationKey] = strings.TrimPrefix(pathField, pathOpts.TrimPrefix)
+		}
+
+		if rewriteValue, ok := annotations[rewriteTargetAnnotationKey]; ok {
+			rewriteValue = collapseSlashes(rewriteValue)
+			rewriteValue = strings.TrimPrefix(rewriteValue, pathOpts.TrimPrefix)
+			// the whole path has been trimmed, forward to the upstream root
+			if rewriteValue == "" {
+				rewriteValue = "/"
+			}
+			annotations[rewriteTargetAnnotationKey] = rewriteValue
+		}
+
+		pathField = collapseSlashes(pathField)
+
+		serviceOpts := &opts.Service
+		if opts.IsPathGRPC(path) {
+			// gRPC routing is prefix based, even for templated paths
+			pathType = pathTypePrefix
+			serviceOpts = grpcServiceOpts(opts.Service)
+		}
+
+		if opts.Ingress.RestrictMethods {
+			// the Ingress of collapsed paths serves the methods of all of them
+			var methods []string
+			for _, collapsedPath := range collapsed[path] {
+				methods = append(methods, enabledMethods(opts, collapsedPath, spec.Paths[collapsedPath])...)
+			}
+
+			if methods := uniqueSortedStrings(methods); len(methods) > 0 {
+				annotations[configurationSnippetAnnotationKey] = generateLimitExceptSnippet(methods)
+			}
+		}
+
+		// a static path which is not rewritten can share an Ingress with the other static paths
+		// as long as it needs the same annotations
+		if opts.Ingress.MergeStatic && pathType == pathTypeExact && pathOpts.Type == "" &&
+			annotations[rewriteTargetAnnotationKey] == pathField && reflect.DeepEqual(ingressOpts, opts.Ingress) {
+			delete(annotations, rewriteTargetAnnotationKey)
+
+			if merged == nil {
+				mergedName, err := g.resourceName(opts, defaultNameTemplate, "", hosts)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				mergedAnnotations = copyAnnotations(annotations)
+				ingress := g.newIngressResource(
+					mergedName,
+					opts.Namespace,
+					pathField,
+					g.pathType(&pathOpts, &opts.Ingress, pathType),
+					annotations,
+					&opts.Service,
+					hosts,
+					&opts.Ingress,
+				)
+				merged = &ingress
+				addRoutes(collapsed[path], pathField, *ingress.Spec.Rules[0].HTTP.Paths[0].PathType, -1)
+				continue
+			}
+
+			if reflect.DeepEqual(annotations, mergedAnnotations) {
+				addIngressPath(merged, pathField)
+				addRoutes(collapsed[path], pathField, *merged.Spec.Rules[0].HTTP.Paths[0].PathType, -1)
+				continue
+			}
+
+			annotations[rewriteTargetAnnotationKey] = pathField
+		}
+
+		pathType = g.pathType(&pathOpts, &opts.Ingress, pathType)
+
+		ingress := g.newIngressResource(
+			name,
+			opts.Namespace,
+			pathField,
+			pathType,
+			annotations,
+			serviceOpts,
+			hosts,
+			&ingressOpts,
+		)
 
-	openApiPathVariableRegex = regexp.MustCompile(`{[A-z]+}`)
-)
+		addRoutes(collapsed[path], pathField, pathType, len(ingresses))
+		ingresses = append(ingresses, ingress)
+		ingressPaths = append(ingressPaths, path)
+	}
 
-func init() {
-	generators.Registry["ingress-nginx"] = &Generator{}
+	if merged != nil {
+		ingresses = append(ingresses, *merged)
+		ingressPaths = append(ingressPaths, "the merged static paths")
+	}
+
+	if err := dedupeNames(ingresses, ingressPaths, opts.Ingress.DedupeNames); err != nil {
+		return nil, nil, err
+	}
+
+	for i, ingress := range routeIngresses {
+		decision := decisionIncluded
+		if ingress < 0 {
+			decision = decisionMerged
+			ingress = len(ingresses) - 1
+		} else if _, ok := collapsed[routes[i].SpecPath]; !ok {
+			// a path collapsed into the kept one only differing by its query string
+			decision = decisionMerged
+		}
+
+		routes[i].Name = ingresses[ingress].Name
+		logPathDecision(opts, routes[i].SpecPath, decision, "ingress", routes[i].Name)
+	}
+
+	return ingresses, routes, nil
 }
 
-type Generator struct{}
+// grpcServiceOpts returns the Service options of gRPC paths, whose backend protocol is GRPC unless GRPCS is set
+func grpcServiceOpts(serviceOpts options.ServiceOptions) *options.ServiceOptions {
+	if serviceOpts.Protocol != options.ServiceProtocolGRPCS {
+		serviceOpts.Protocol = options.ServiceProtocolGRPC
+	}
 
-func (g *Generator) Cmd() string {
-	return "ingress-nginx"
+	return &serviceOpts
 }
 
-func (g *Generator) Flags() *pflag.FlagSet {
-	fs := pflag.NewFlagSet("ingress-nginx", pflag.ExitOnError)
+// collapseQueryPaths returns the enabled spec paths to generate an Ingress for, collapsing the paths which only differ
+// by their query string, e.g. /search?type=books and /search?type=authors, as Ingress can't route on query strings.
+// The first of such paths is kept, along with all the paths collapsed into each kept one, and a warning is logged.
+func collapseQueryPaths(opts *options.Options, spec *openapi3.T) ([]string, map[string][]string) {
+	var paths []string
+	collapsed := map[string][]string{}
 
-	fs.String(
-		"path.base",
-		"/",
-		"a base path for Service endpoints",
-	)
+	// the kept path for each routed path
+	kept := map[string]string{}
 
-	fs.String(
-		"path.trim_prefix",
-		"",
-		"a prefix to trim from the URL before forwarding to the upstream Service",
-	)
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		if opts.IsPathFilteredOut(path) {
+			logPathDecision(opts, path, decisionExcluded, "reason", "path.include or path.exclude")
+			continue
+		}
 
-	fs.Bool(
-		"path.split",
-		false,
-		"force Kusk to generate a separate Ingress for each operation",
-	)
+		if opts.IsPathDisabled(path) {
+			logPathDecision(opts, path, decisionDisabled)
+			continue
+		}
 
-	fs.String(
-		"host",
-		"",
-		"an Ingress Host to listen on",
-	)
+		if !includesMethods(opts, path, spec.Paths[path]) {
+			logPathDecision(opts, path, decisionExcluded, "reason", "no operation with one of path.methods")
+			continue
+		}
 
-	fs.Uint32(
-		"rate_limits.rps",
-		0,
-		"request per second rate limit",
-	)
+		routedPath := stripQueryString(path)
 
-	fs.Uint32(
-		"rate_limits.burst",
-		0,
-		"request per second burst",
-	)
+		keptPath, ok := kept[routedPath]
+		if !ok {
+			kept[routedPath] = path
+			paths = append(paths, path)
+			collapsed[path] = []string{path}
+			continue
+		}
 
-	fs.Uint32(
-		"timeouts.request_timeout",
-		0,
-		"total request timeout (seconds)",
-	)
+		log.New(warnOutput, "WARN", log.Lmsgprefix).
+			Printf("%s and %s only differ by their query string which Ingress can't route on, a single Ingress is generated for %s", keptPath, path, routedPath)
 
-	fs.String(
-		"nginx_ingress.rewrite_target",
-		"",
-		"a custom NGINX rewrite target",
-	)
+		collapsed[keptPath] = append(collapsed[keptPath], path)
+	}
 
-	return fs
+	return paths, collapsed
 }
 
-func (g *Generator) ShortDescription() string {
-	return "Generates ingress-nginx resources"
-}
+// stripQueryString returns the path without its query string, if any
+func stripQueryString(path string) string {
+	if i := strings.Index(path, "?"); i >= 0 {
+		return path[:i]
+	}
 
-func (g *Generator) LongDescription() string {
-	return g.ShortDescription()
+	return path
 }
 
-func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
-	if err := opts.FillDefaultsAndValidate(); err != nil {
-		return "", fmt.Errorf("failed to validate opts: %w", err)
-	}
+// uniqueSortedStrings returns the sorted strings without duplicates
+func uniqueSortedStrings(values []string) []string {
+	sort.Strings(values)
 
-	ingresses := make([]v1.Ingress, 0)
+	var unique []string
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
 
-	if g.shouldSplit(opts, spec) {
-		for path := range spec.Paths {
-			if opts.IsPathDisabled(path) {
-				continue
-			}
+	return unique
+}
 
-			name := fmt.Sprintf("%s-%s", opts.Service.Name, ingressResourceNameFromPath(path))
+// dedupeNames checks that the Ingresses generated for different paths have unique names.
+// If dedupe is set, the colliding names are suffixed with a counter, the first Ingress keeping its name.
+// Otherwise, a collision is an error listing the conflicting paths.
+func dedupeNames(ingresses []v1.Ingress, paths []string, dedupe bool) error {
+	// the path of the Ingress of each name taken so far
+	names := make(map[string]string, len(ingresses))
 
-			corsOpts := opts.GetCORSOpts(path, "")
-			rateLimitOpts := opts.GetRateLimitOpts(path, "")
-			timeoutOpts := opts.GetTimeoutOpts(path, "")
+	for i := range ingresses {
+		name := ingresses[i].Name
 
-			// Get initial set of annotation based on current options
-			// will be modified next based on current path
-			annotations := g.generateAnnotations(
-				&opts.Path,
-				&opts.NGINXIngress,
-				&corsOpts,
-				&rateLimitOpts,
-				&timeoutOpts,
-			)
-
-			// if path has a parameter, replace {param} with ([A-z0-9]+) and set use regex annotation to true
-			// if path has no parameter, just use path
-			var pathField string
-			if openApiPathVariableRegex.MatchString(path) {
-				pathField = opts.Path.Base + string(openApiPathVariableRegex.ReplaceAll([]byte(path), []byte("([A-z0-9]+)")))
-
-				// get the first capture group of regex. Given a path /books/{id}, will return /books/
-				rewrite := opts.Path.Base + string(openApiPathVariableRegex.ReplaceAllLiteral([]byte(path), []byte("$1")))
-				annotations[rewriteTargetAnnotationKey] = rewrite
-				annotations[useRegexAnnotationKey] = "true"
-			} else if path == "/" {
-				pathField = opts.Path.Base + "$"
-				annotations[rewriteTargetAnnotationKey] = opts.Path.Base + "/"
-				annotations[useRegexAnnotationKey] = "true"
-			} else {
-				pathField = opts.Path.Base + path
-				annotations[rewriteTargetAnnotationKey] = strings.TrimPrefix(pathField, opts.Path.TrimPrefix)
+		if path, ok := names[name]; ok {
+			if !dedupe {
+				return fmt.Errorf(
+					"%s and %s would both generate an Ingress named %s, use ingress.dedupe_names to disambiguate them",
+					path,
+					paths[i],
+					name,
+				)
 			}
 
-			if rewriteValue, ok := annotations[rewriteTargetAnnotationKey]; ok {
-				rewriteValue = strings.ReplaceAll(rewriteValue, "//", "/")
-				rewriteValue = strings.TrimPrefix(rewriteValue, opts.Path.TrimPrefix)
-				annotations[rewriteTargetAnnotationKey] = rewriteValue
+			for n := 2; ok; n++ {
+				name = sanitizeResourceName(fmt.Sprintf("%s-%d", ingresses[i].Name, n))
+				_, ok = names[name]
 			}
 
-			// Replace // with /
-			pathField = strings.ReplaceAll(pathField, "//", "/")
+			ingresses[i].Name = name
+		}
+
+		names[name] = paths[i]
+	}
+
+	return nil
+}
+
+// addIngressPath adds the given path to each rule of the Ingress, with the same path type and backend as the existing ones
+func addIngressPath(ingress *v1.Ingress, path string) {
+	for _, rule := range ingress.Spec.Rules {
+		first := rule.HTTP.Paths[0]
+		rule.HTTP.Paths = append(rule.HTTP.Paths, v1.HTTPIngressPath{
+			PathType: first.PathType,
+			Path:     path,
+			Backend:  first.Backend,
+		})
+	}
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	annotationsCopy := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		annotationsCopy[key] = value
+	}
+
+	return annotationsCopy
+}
 
-			ingress := g.newIngressResource(
-				name,
-				opts.Namespace,
-				pathField,
-				pathTypeExact,
-				annotations,
-				&opts.Service,
-				opts.Host,
-			)
+// pathRateLimitOpts returns the rate limits for the Ingress of the path, taking operation level rate limits into account.
+// As ingress-nginx can't rate limit the methods of a path separately, the lowest rate limit applies
+// when the operations of the path have different ones.
+func (g *Generator) pathRateLimitOpts(opts *options.Options, path string, pathItem *openapi3.PathItem) options.RateLimitOptions {
+	rateLimitOpts := opts.GetRateLimitOpts(path, "")
 
-			ingresses = append(ingresses, ingress)
+	for i, method := range enabledMethods(opts, path, pathItem) {
+		opRateLimitOpts := opts.GetRateLimitOpts(path, method)
+		if i == 0 {
+			rateLimitOpts = opRateLimitOpts
+			continue
+		}
+
+		if opRateLimitOpts == rateLimitOpts {
+			continue
+		}
+
+		log.New(warnOutput, "WARN", log.Lmsgprefix).
+			Printf("the operations of %s have different rate limits which ingress-nginx can't apply per method, the lowest one is used", path)
+
+		// zero RPS means no rate limit
+		if rateLimitOpts.RPS == 0 || (opRateLimitOpts.RPS != 0 && opRateLimitOpts.RPS < rateLimitOpts.RPS) {
+			rateLimitOpts = opRateLimitOpts
 		}
-	} else if !opts.Disabled {
-		ingress := g.newIngressResource(
-			fmt.Sprintf("%s-ingress", opts.Service.Name),
-			opts.Namespace,
-			g.generatePath(&opts.Path, &opts.NGINXIngress),
-			pathTypePrefix,
-			g.generateAnnotations(&opts.Path, &opts.NGINXIngress, &opts.CORS, &opts.RateLimits, &opts.Timeouts),
-			&opts.Service,
-			opts.Host,
-		)
-		ingresses = append(ingresses, ingress)
 	}
 
-	// We need to sort the ingresses as in the process of conversion of YAML to JSON
-	// the Go map's access mechanics randomize the order and therefore the output is shuffled.
-	// Not only it makes tests fail, it would also affect people who would use this in order to
-	// generate manifests and use them in GitOps processes
-	sort.Slice(ingresses, func(i, j int) bool {
-		return ingresses[i].Name < ingresses[j].Name
-	})
+	return rateLimitOpts
+}
+
+// enabledMethods returns the sorted methods of the path operations which are not disabled
+func enabledMethods(opts *options.Options, path string, pathItem *openapi3.PathItem) []string {
+	var methods []string
+	for method := range pathItem.Operations() {
+		if opts.IsOperationDisabled(path, method) {
+			continue
+		}
+
+		methods = append(methods, method)
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
 
-	return buildOutput(ingresses)
+// includesMethods returns whether the path has an enabled operation with one of the path.methods, if set
+func includesMethods(opts *options.Options, path string, pathItem *openapi3.PathItem) bool {
+	return len(opts.Path.Methods) == 0 || len(enabledMethods(opts, path, pathItem)) > 0
 }
 
-// Build suitable output to be piped into kubectl or a file
-func buildOutput(ingresses []v1.Ingress) (string, error) {
-	var builder strings.Builder
+// variableCaptureGroup returns the capture group matching a path variable
+func variableCaptureGroup(opts *options.Options) string {
+	if opts.Path.VariableRegex != "" {
+		return "(" + opts.Path.VariableRegex + ")"
+	}
+
+	return "(" + defaultVariableRegex + ")"
+}
+
+// positionalPathVariables replaces each path variable with a reference to its capture group, i.e. $1, $2 and so on
+func positionalPathVariables(path string) string {
+	position := 0
+
+	return openApiPathVariableRegex.ReplaceAllStringFunc(path, func(string) string {
+		position++
+		return fmt.Sprintf("$%d", position)
+	})
+}
+
+// buildFiles puts each Ingress into a separate file named after the Ingress.
+// Two paths resulting in the same Ingress name are reported as an error instead of overwriting one another.
+func buildFiles(ingresses []runtime.Object) (generators.Files, error) {
+	files := generators.Files{}
 
 	for _, ingress := range ingresses {
-		builder.WriteString("---\n") // indicate start of YAML resource
-		b, err := yaml.Marshal(ingress)
+		metadata, err := meta.Accessor(ingress)
 		if err != nil {
-			return "", fmt.Errorf("unable to marshal ingress resource: %+v: %s", ingress, err.Error())
+			return nil, err
+		}
+
+		b, err := generators.Marshal(ingress)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal ingress resource: %+v: %s", ingress, err.Error())
+		}
+
+		if err := files.Add(metadata.GetName(), "---\n"+string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// ingressObjects returns the Ingresses in the API version set by ingress.api_version, networking.k8s.io/v1 by default
+func ingressObjects(ingressOpts *options.IngressOptions, ingresses []v1.Ingress) []runtime.Object {
+	objects := make([]runtime.Object, 0, len(ingresses)+2)
+
+	for i := range ingresses {
+		if ingressOpts.APIVersion == ingressV1beta1APIVersion {
+			objects = append(objects, toV1beta1(&ingresses[i]))
+			continue
 		}
-		builder.WriteString(string(b))
+
+		objects = append(objects, &ingresses[i])
+	}
+
+	return objects
+}
+
+// collapseSlashes replaces repeated slashes with a single one, e.g. when joining a base path ending with a slash
+func collapseSlashes(path string) string {
+	return repeatedSlashesRegex.ReplaceAllString(path, "/")
+}
+
+// rootPath returns the path the spec root path is exposed at, which is the base path without a trailing slash,
+// so that both /api and /api/ bases expose the root at /api
+func rootPath(base string) string {
+	root := strings.TrimSuffix(collapseSlashes(base), "/")
+	if root == "" {
+		return "/"
+	}
+
+	return root
+}
+
+// resourceNameData is the data available to ingress.name_template
+type resourceNameData struct {
+	Service   string
+	Path      string
+	Namespace string
+	Host      string
+}
+
+// resourceName renders ingress.name_template, or the given default template if not set,
+// and prepends name_prefix into a valid RFC 1123 resource name
+func (g *Generator) resourceName(opts *options.Options, defaultTemplate string, path string, hosts []string) (string, error) {
+	nameTemplate := opts.Ingress.NameTemplate
+	if nameTemplate == "" {
+		nameTemplate = defaultTemplate
+	}
+
+	tmpl, err := template.New("name").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ingress.name_template: %w", err)
+	}
+
+	data := resourceNameData{
+		Service:   opts.Service.Name,
+		Path:      path,
+		Namespace: opts.Namespace,
+	}
+	if len(hosts) > 0 {
+		data.Host = hosts[0]
+	}
+
+	var name strings.Builder
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("failed to render ingress.name_template: %w", err)
 	}
 
-	return builder.String(), nil
+	if sanitizeResourceName(name.String()) == "" {
+		return "", fmt.Errorf("ingress.name_template %q produced an empty resource name", nameTemplate)
+	}
+
+	// the prefix is sanitized along with the name so that their combination fits the length limit
+	return sanitizeResourceName(opts.NamePrefix + name.String()), nil
 }
 
 // Given a path such as /books/{id} return a suitable ingress resource name
@@ -220,19 +1258,34 @@ func ingressResourceNameFromPath(path string) string {
 		return "root"
 	}
 
-	var b strings.Builder
-	for _, pathItem := range strings.Split(path, "/") {
-		if pathItem == "" {
-			continue
-		}
+	name := sanitizeResourceName(path)
+	if name == "" {
+		return "root"
+	}
+
+	return name
+}
 
-		// remove openapi path variable curly braces from path item
-		strippedPathItem := strings.ReplaceAll(strings.ReplaceAll(pathItem, "{", ""), "}", "")
-		fmt.Fprintf(&b, "%s-", strippedPathItem)
+// sanitizeResourceName turns the given name into a valid RFC 1123 label:
+// characters outside of [a-z0-9-] are replaced with a dash, consecutive dashes are collapsed
+// and leading/trailing dashes trimmed. Names longer than 63 characters are truncated and suffixed
+// with a short hash of the full name so that different long names don't collide.
+func sanitizeResourceName(name string) string {
+	sanitized := strings.ToLower(name)
+	sanitized = invalidResourceNameCharsRegex.ReplaceAllString(sanitized, "-")
+	sanitized = strings.Trim(sanitized, "-")
+
+	if len(sanitized) <= maxResourceNameLength {
+		return sanitized
 	}
 
-	// remove trailing - character
-	return strings.ToLower(strings.TrimSuffix(b.String(), "-"))
+	hash := fnv.New32a()
+	hash.Write([]byte(sanitized))
+	suffix := fmt.Sprintf("%08x", hash.Sum32())
+
+	truncated := strings.TrimRight(sanitized[:maxResourceNameLength-len(suffix)-1], "-")
+
+	return truncated + "-" + suffix
 }
 
 func (g *Generator) newIngressResource(
@@ -242,8 +1295,83 @@ func (g *Generator) newIngressResource(
 	pathType v1.PathType,
 	annotations map[string]string,
 	serviceOpts *options.ServiceOptions,
-	host string,
+	hosts []string,
+	ingressOpts *options.IngressOptions,
 ) v1.Ingress {
+	generateSSLRedirectAnnotations(annotations, ingressOpts)
+	if ingressOpts.ProxyBodySize != "" {
+		annotations[proxyBodySizeAnnotationKey] = ingressOpts.ProxyBodySize
+	}
+	generateAuthAnnotations(annotations, &ingressOpts.Auth)
+	generateAffinityAnnotations(annotations, &ingressOpts.Affinity)
+	generateWebsocketAnnotations(annotations, ingressOpts)
+	if ingressOpts.ProxyBuffering != "" {
+		annotations[proxyBufferingAnnotationKey] = ingressOpts.ProxyBuffering
+	}
+	if len(ingressOpts.WhitelistSourceRange) > 0 {
+		annotations[whitelistSourceRangeAnnotationKey] = strings.Join(ingressOpts.WhitelistSourceRange, ",")
+	}
+	generateHeadersAnnotations(annotations, ingressOpts)
+	generateSlashAnnotations(annotations, path, pathType, ingressOpts)
+	if serviceOpts.Protocol != "" {
+		annotations[backendProtocolAnnotationKey] = serviceOpts.Protocol
+	}
+	dropNGINXAnnotations(annotations, ingressOpts.Controller, name)
+
+	// user-provided annotations take precedence over the generated ones
+	for key, value := range ingressOpts.Annotations {
+		annotations[key] = value
+	}
+
+	backend := v1.IngressBackend{
+		Service: &v1.IngressServiceBackend{
+			Name: serviceOpts.Name,
+			Port: v1.ServiceBackendPort{
+				Name:   serviceOpts.PortName,
+				Number: serviceOpts.Port,
+			},
+		},
+	}
+
+	var defaultBackend *v1.IngressBackend
+	if ingressOpts.DefaultBackend {
+		defaultBackend = backend.DeepCopy()
+	}
+
+	// the IngressClass defaults to the one ingress controllers are usually installed with
+	ingressClassName := defaultIngressClassName
+	if ingressOpts.Controller != "" {
+		ingressClassName = ingressOpts.Controller
+	}
+
+	if ingressOpts.Class != "" {
+		ingressClassName = ingressOpts.Class
+	}
+
+	// a rule without a host matches all incoming requests
+	ruleHosts := hosts
+	if len(ruleHosts) == 0 {
+		ruleHosts = []string{""}
+	}
+
+	rules := make([]v1.IngressRule, 0, len(ruleHosts))
+	for _, host := range ruleHosts {
+		rules = append(rules, v1.IngressRule{
+			Host: host,
+			IngressRuleValue: v1.IngressRuleValue{
+				HTTP: &v1.HTTPIngressRuleValue{
+					Paths: []v1.HTTPIngressPath{
+						{
+							PathType: &pathType,
+							Path:     path,
+							Backend:  backend,
+						},
+					},
+				},
+			},
+		})
+	}
+
 	return v1.Ingress{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: ingressAPIVersion,
@@ -253,32 +1381,74 @@ func (g *Generator) newIngressResource(
 			Name:        name,
 			Namespace:   namespace,
 			Annotations: annotations,
+			Labels:      generateLabels(ingressOpts),
 		},
 		Spec: v1.IngressSpec{
 			IngressClassName: &ingressClassName,
-			Rules: []v1.IngressRule{
-				{
-					Host: host,
-					IngressRuleValue: v1.IngressRuleValue{
-						HTTP: &v1.HTTPIngressRuleValue{
-							Paths: []v1.HTTPIngressPath{
-								{
-									PathType: &pathType,
-									Path:     path,
-									Backend: v1.IngressBackend{
-										Service: &v1.IngressServiceBackend{
-											Name: serviceOpts.Name,
-											Port: v1.ServiceBackendPort{
-												Number: serviceOpts.Port,
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
+			DefaultBackend:   defaultBackend,
+			TLS:              g.generateTLS(&ingressOpts.TLS, hosts),
+			Rules:            rules,
+		},
+	}
+}
+
+// hosts returns the list of hosts to generate Ingress rules for.
+// Host option takes precedence, otherwise hosts are taken from the spec servers URLs.
+// No hosts are returned for the * host, Ingress rules without a host matching all of them.
+func (g *Generator) hosts(opts *options.Options, spec *openapi3.T) []string {
+	if opts.Host == "*" {
+		return nil
+	}
+
+	if opts.Host != "" {
+		return []string{opts.Host}
+	}
+
+	return kuskSpec.ServerHosts(spec.Servers)
+}
+
+// serverBasePath returns the base path the spec servers URLs share,
+// warning when they don't agree on one
+func (g *Generator) serverBasePath(spec *openapi3.T) string {
+	base, ok := kuskSpec.ServerBasePath(spec.Servers)
+	if !ok {
+		log.New(warnOutput, "WARN", log.Lmsgprefix).
+			Printf("the spec servers URLs have different paths, using / as path.base")
+	}
+
+	return base
+}
+
+// pathType returns a path type forced by the user, with path.type taking precedence over ingress.path_type,
+// or the given default one
+func (g *Generator) pathType(pathOpts *options.PathOptions, ingressOpts *options.IngressOptions, defaultPathType v1.PathType) v1.PathType {
+	if pathOpts.Type != "" {
+		return v1.PathType(pathOpts.Type)
+	}
+
+	if ingressOpts.PathType != "" {
+		return v1.PathType(ingressOpts.PathType)
+	}
+
+	return defaultPathType
+}
+
+// generateTLS returns the TLS section for the generated ingresses or nil if TLS secret is not set.
+// TLS hosts default to the Ingress rules hosts.
+func (g *Generator) generateTLS(tlsOpts *options.IngressTLSOptions, ingressHosts []string) []v1.IngressTLS {
+	if tlsOpts.SecretName == "" {
+		return nil
+	}
+
+	hosts := tlsOpts.Hosts
+	if len(hosts) == 0 {
+		hosts = ingressHosts
+	}
+
+	return []v1.IngressTLS{
+		{
+			Hosts:      hosts,
+			SecretName: tlsOpts.SecretName,
 		},
 	}
 }
@@ -297,7 +1467,7 @@ func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 		}
 
 		if ro.Group != "" {
-			log.New(os.Stderr, "WARN", log.Lmsgprefix).
+			log.New(warnOutput, "WARN", log.Lmsgprefix).
 				Printf("ingress-nginx does not support rate limit groups. These will be ignored")
 
 			groupUnsupportedWarned = true
@@ -306,13 +1476,34 @@ func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 
 	warnGroupUnsupported(opts.RateLimits)
 
-	for path, pathItem := range spec.Paths {
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
 		// a path is disabled
 		if opts.IsPathDisabled(path) {
 			return true
 		}
 
+		// a path has no operation with one of the path.methods
+		if !includesMethods(opts, path, pathItem) {
+			return true
+		}
+
+		// a path requires a different authentication than the global one as per its security requirements
+		if !reflect.DeepEqual(pathAuthOpts(opts, spec, path), opts.Ingress.Auth) {
+			return true
+		}
+
+		// a path is served by gRPC unlike the others, or the other way around
+		if opts.IsPathGRPC(path) != opts.GRPC {
+			return true
+		}
+
 		if pathSubOptions, ok := opts.PathSubOptions[path]; ok {
+			// a path has a different from global scope base path or trim prefix
+			if !reflect.DeepEqual(opts.GetPathOpts(path, ""), opts.Path) {
+				return true
+			}
+
 			// a path has non-zero, different from global scope CORS options
 			if !reflect.DeepEqual(options.CORSOptions{}, pathSubOptions.CORS) &&
 				!reflect.DeepEqual(opts.CORS, pathSubOptions.CORS) {
@@ -326,7 +1517,7 @@ func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 				warnGroupUnsupported(pathSubOptions.RateLimits)
 
 				if !rateLimitWarned {
-					log.New(os.Stderr, "WARN", log.Lmsgprefix).
+					log.New(warnOutput, "WARN", log.Lmsgprefix).
 						Printf("Setting a rate limit option on the path level would cause a separate rate limit applied for each path")
 
 					rateLimitWarned = true
@@ -335,16 +1526,39 @@ func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 				return true
 			}
 
-			// a path has non-zero, different from global scope timeouts options
-			if !reflect.DeepEqual(options.TimeoutOptions{}, pathSubOptions.Timeouts) &&
-				!reflect.DeepEqual(opts.Timeouts, pathSubOptions.Timeouts) {
+			// a path has different from global scope timeouts
+			if opts.GetTimeoutOpts(path, "") != opts.Timeouts {
+				return true
+			}
+
+			// a path has non-zero, different from global scope retry options
+			if !reflect.DeepEqual(options.RetryOptions{}, pathSubOptions.Retries) &&
+				!reflect.DeepEqual(opts.Retries, pathSubOptions.Retries) {
+				return true
+			}
+
+			// a path has a different from global scope class, SSL redirect, proxy body size, websocket support, proxy buffering
+			// or source range
+			if !reflect.DeepEqual(opts.GetIngressOpts(path, ""), opts.Ingress) {
 				return true
 			}
 		}
 
+		// an operation has rate limits different from the path ones
+		if g.pathRateLimitOpts(opts, path, pathItem) != opts.GetRateLimitOpts(path, "") {
+			return true
+		}
+
 		for method := range pathItem.Operations() {
-			if _, ok := opts.OperationSubOptions[method+path]; ok {
-				log.New(os.Stderr, "WARN", log.Lmsgprefix).
+			opSubOptions, ok := opts.OperationSubOptions[method+path]
+			if !ok {
+				continue
+			}
+
+			// operation level rate limits are applied to the Ingress of the path
+			opSubOptions.RateLimits = options.RateLimitOptions{}
+			if !reflect.DeepEqual(options.SubOptions{}, opSubOptions) {
+				log.New(warnOutput, "WARN", log.Lmsgprefix).
 					Printf("HTTP Method level options detected which ingress-nginx doesn't support. These will be ignored")
 
 				break // Only need to warn users once
@@ -1,12 +1,21 @@
 package nginx_ingress
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 
+	"github.com/kubeshop/kusk/generators"
 	"github.com/kubeshop/kusk/options"
 	"github.com/kubeshop/kusk/spec"
 )
@@ -18,6 +27,16 @@ type testCase struct {
 	res     string
 }
 
+const singlePathSpec = `
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`
+
 func TestNGINXIngress(t *testing.T) {
 	trueValue := true
 	falseValue := false
@@ -40,7 +59,8 @@ func TestNGINXIngress(t *testing.T) {
 apiVersion: networking.k8s.io/v1
 kind: Ingress
 metadata:
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: default
 spec:
@@ -55,8 +75,6 @@ spec:
               number: 80
         path: /
         pathType: Prefix
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -76,7 +94,8 @@ status:
 apiVersion: networking.k8s.io/v1
 kind: Ingress
 metadata:
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: default
 spec:
@@ -91,8 +110,6 @@ spec:
               number: 80
         path: /somepath
         pathType: Prefix
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -115,7 +132,8 @@ kind: Ingress
 metadata:
   annotations:
     nginx.ingress.kubernetes.io/rewrite-target: /$2
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: default
 spec:
@@ -130,8 +148,6 @@ spec:
               number: 80
         path: /somepath(/|$)(.*)
         pathType: Prefix
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -157,7 +173,8 @@ kind: Ingress
 metadata:
   annotations:
     nginx.ingress.kubernetes.io/rewrite-target: /someotherpath
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: default
 spec:
@@ -172,8 +189,6 @@ spec:
               number: 80
         path: /somepath
         pathType: Prefix
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -213,7 +228,8 @@ metadata:
     nginx.ingress.kubernetes.io/cors-max-age: "120"
     nginx.ingress.kubernetes.io/enable-cors: "true"
     nginx.ingress.kubernetes.io/rewrite-target: /someotherpath
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: default
 spec:
@@ -228,8 +244,6 @@ spec:
               number: 80
         path: /somepath
         pathType: Prefix
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -328,7 +342,8 @@ metadata:
     nginx.ingress.kubernetes.io/enable-cors: "true"
     nginx.ingress.kubernetes.io/rewrite-target: /books/$1
     nginx.ingress.kubernetes.io/use-regex: "true"
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-books-id
   namespace: booksapp
 spec:
@@ -341,10 +356,8 @@ spec:
             name: webapp
             port:
               number: 7000
-        path: /bookstore/books/([A-z0-9]+)
-        pathType: Exact
-status:
-  loadBalancer: {}
+        path: /bookstore/books/([^/]+)
+        pathType: ImplementationSpecific
 ---
 apiVersion: networking.k8s.io/v1
 kind: Ingress
@@ -358,7 +371,8 @@ metadata:
     nginx.ingress.kubernetes.io/enable-cors: "true"
     nginx.ingress.kubernetes.io/rewrite-target: /
     nginx.ingress.kubernetes.io/use-regex: "true"
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-root
   namespace: booksapp
 spec:
@@ -373,8 +387,6 @@ spec:
               number: 7000
         path: /bookstore$
         pathType: Exact
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -429,7 +441,8 @@ metadata:
     nginx.ingress.kubernetes.io/proxy-read-timeout: "5"
     nginx.ingress.kubernetes.io/proxy-send-timeout: "5"
     nginx.ingress.kubernetes.io/rewrite-target: /$2
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: booksapp
 spec:
@@ -444,8 +457,75 @@ spec:
               number: 7000
         path: /bookstore(/|$)(.*)
         pathType: Prefix
-status:
-  loadBalancer: {}
+`,
+		},
+		{
+			name: "timeouts unset, no timeout annotations",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Timeouts: options.TimeoutOptions{
+					RequestTimeout: 0,
+					IdleTimeout:    0,
+				},
+			},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+`,
+		},
+		{
+			name: "rate limits unset, no rate limit annotations",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				RateLimits: options.RateLimitOptions{},
+			},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
 `,
 		},
 		{
@@ -501,7 +581,8 @@ metadata:
     nginx.ingress.kubernetes.io/limit-burst-multiplier: "4"
     nginx.ingress.kubernetes.io/limit-rps: "100"
     nginx.ingress.kubernetes.io/rewrite-target: /$2
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: webapp-ingress
   namespace: booksapp
 spec:
@@ -516,15 +597,14 @@ spec:
               number: 7000
         path: /bookstore(/|$)(.*)
         pathType: Prefix
-status:
-  loadBalancer: {}
 `,
 		},
 		{
 			name: "globally disabled",
 			options: options.Options{
-				Disabled:  true,
-				Namespace: "default",
+				Disabled:   true,
+				AllowEmpty: true,
+				Namespace:  "default",
 				Service: options.ServiceOptions{
 					Namespace: "default",
 					Name:      "petstore",
@@ -582,7 +662,8 @@ kind: Ingress
 metadata:
   annotations:
     nginx.ingress.kubernetes.io/rewrite-target: /path
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: petstore-path
   namespace: default
 spec:
@@ -597,8 +678,6 @@ spec:
               number: 80
         path: /path
         pathType: Exact
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -637,7 +716,8 @@ kind: Ingress
 metadata:
   annotations:
     nginx.ingress.kubernetes.io/rewrite-target: /path
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: petstore-path
   namespace: default
 spec:
@@ -652,8 +732,6 @@ spec:
               number: 80
         path: /path
         pathType: Exact
-status:
-  loadBalancer: {}
 `,
 		},
 		{
@@ -698,7 +776,8 @@ kind: Ingress
 metadata:
   annotations:
     nginx.ingress.kubernetes.io/rewrite-target: /api/path
-  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kusk
   name: petstore-path
   namespace: default
 spec:
@@ -713,23 +792,5256 @@ spec:
               number: 80
         path: /api/path
         pathType: Exact
-status:
-  loadBalancer: {}
 `,
 		},
-	}
-
-	var gen Generator
-
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			r := require.New(t)
-
-			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
-			r.NoError(err)
-			profile, err := gen.Generate(&testCase.options, spec)
-			r.NoError(err)
-			r.Equal(testCase.res, profile)
+		{
+			name: "no CORS options set, no CORS annotations",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				CORS: options.CORSOptions{
+					Credentials: nil,
+				},
+			},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+`,
+		},
+		{
+			name: "CORS credentials disabled",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				CORS: options.CORSOptions{
+					Origins:     []string{"*"},
+					Credentials: &falseValue,
+				},
+			},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/cors-allow-credentials: "false"
+    nginx.ingress.kubernetes.io/cors-allow-origin: '*'
+    nginx.ingress.kubernetes.io/enable-cors: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+`,
+		},
+		{
+			name: "path level base path and CORS override",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "petstore",
+				},
+				Path: options.PathOptions{
+					Base: "/api",
+				},
+				PathSubOptions: map[string]options.SubOptions{
+					"/legacy": {
+						Path: options.PathOptions{
+							Base: "/v1",
+						},
+						CORS: options.CORSOptions{
+							Origins: []string{"http://foo.example"},
+						},
+					},
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /pets:
+    get: {}
+  /legacy:
+    x-kusk:
+      path:
+        base: /v1
+      cors:
+        origins:
+          - http://foo.example
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/cors-allow-origin: http://foo.example
+    nginx.ingress.kubernetes.io/enable-cors: "true"
+    nginx.ingress.kubernetes.io/rewrite-target: /v1/legacy
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: petstore-legacy
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /v1/legacy
+        pathType: Exact
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /api/pets
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: petstore-pets
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /api/pets
+        pathType: Exact
+`,
+		},
+		{
+			name: "restrict methods in split mode",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "petstore",
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Ingress: options.IngressOptions{
+					RestrictMethods: true,
+				},
+				OperationSubOptions: map[string]options.SubOptions{
+					"DELETE/pets": {
+						Disabled: &trueValue,
+					},
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /pets:
+    get: {}
+    post: {}
+    delete: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/configuration-snippet: |
+      limit_except GET POST {
+        deny all;
+      }
+    nginx.ingress.kubernetes.io/rewrite-target: /pets
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: petstore-pets
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: petstore
+            port:
+              number: 80
+        path: /pets
+        pathType: Exact
+`,
+		},
+		{
+			name: "TLS secret set, hosts default to ingress host",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Host: "example.com",
+				Ingress: options.IngressOptions{
+					TLS: options.IngressTLSOptions{
+						SecretName: "example-tls",
+					},
+				},
+			},
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+  tls:
+  - hosts:
+    - example.com
+    secretName: example-tls
+`,
+		},
+		{
+			name: "TLS secret and hosts set in split mode",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Host: "example.com",
+				Ingress: options.IngressOptions{
+					TLS: options.IngressTLSOptions{
+						SecretName: "example-tls",
+						Hosts:      []string{"example.com", "www.example.com"},
+					},
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /books:
+    get: {}
+  /authors:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /authors
+    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-authors
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /authors
+        pathType: Exact
+  tls:
+  - hosts:
+    - example.com
+    - www.example.com
+    secretName: example-tls
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /books
+    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-books
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /books
+        pathType: Exact
+  tls:
+  - hosts:
+    - example.com
+    - www.example.com
+    secretName: example-tls
+`,
+		},
+		{
+			name: "custom annotations applied to split ingresses",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Ingress: options.IngressOptions{
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/rewrite-target": "/custom",
+						"nginx.ingress.kubernetes.io/ssl-redirect":   "false",
+					},
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /books:
+    get: {}
+  /authors:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /custom
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-authors
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /authors
+        pathType: Exact
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /custom
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-books
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /books
+        pathType: Exact
+`,
+		},
+		{
+			name: "trim prefix with nested base path in split mode",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Base:       "/api/v1",
+					TrimPrefix: "/api/v1",
+					Split:      true,
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /books:
+    get: {}
+  /books/{id}/reviews:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /books
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-books
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /api/v1/books
+        pathType: Exact
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /books/$1/reviews
+    nginx.ingress.kubernetes.io/use-regex: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-books-id-reviews
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /api/v1/books/([^/]+)/reviews
+        pathType: ImplementationSpecific
+`,
+		},
+		{
+			name: "trim prefix with nested base path",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Base:       "/api/v1",
+					TrimPrefix: "/api/v1",
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /$2
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /api/v1(/|$)(.*)
+        pathType: Prefix
+`,
+		},
+		{
+			name: "trim prefix covering the whole path in split mode",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Base:       "/api",
+					TrimPrefix: "/api/books",
+					Split:      true,
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+paths:
+  /books:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-books
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /api/books
+        pathType: Exact
+`,
+		},
+		{
+			name: "hosts taken from servers",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+servers:
+  - url: https://api.example.com/
+  - url: https://api.example.com:8443/
+  - url: https://api.example.org/
+paths:
+  /books:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: api.example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+  - host: api.example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+`,
+		},
+		{
+			name: "hosts taken from servers in split mode",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+servers:
+  - url: https://api.example.com/
+  - url: https://api.example.org/
+paths:
+  /books:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /books
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-books
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: api.example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /books
+        pathType: Exact
+  - host: api.example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /books
+        pathType: Exact
+`,
+		},
+		{
+			name: "host option takes precedence over servers",
+			options: options.Options{
+				Namespace: "default",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Host: "example.com",
+			},
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+servers:
+  - url: https://api.example.com/
+paths:
+  /books:
+    get: {}
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 80
+        path: /
+        pathType: Prefix
+`,
+		},
+	}
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			if testCase.spec == "" {
+				// the single Ingress doesn't depend on the paths, as long as there is one
+				testCase.spec = singlePathSpec
+			}
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err)
+			profile, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, profile)
+		})
+	}
+}
+
+func TestPathType(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pathType       string
+		pathSubOptions map[string]options.SubOptions
+		res            map[string]v1.PathType
+	}{
+		{
+			name: "path type chosen based on path shape",
+			res: map[string]v1.PathType{
+				"webapp-root":     v1.PathTypeExact,
+				"webapp-books":    v1.PathTypeExact,
+				"webapp-books-id": v1.PathTypeImplementationSpecific,
+			},
+		},
+		{
+			name:     "path type forced",
+			pathType: "Prefix",
+			res: map[string]v1.PathType{
+				"webapp-root":     v1.PathTypePrefix,
+				"webapp-books":    v1.PathTypePrefix,
+				"webapp-books-id": v1.PathTypePrefix,
+			},
+		},
+		{
+			name: "path type forced on a path",
+			pathSubOptions: map[string]options.SubOptions{
+				"/books/{id}": {
+					Path: options.PathOptions{
+						Type: "Prefix",
+					},
+				},
+			},
+			res: map[string]v1.PathType{
+				"webapp-root":     v1.PathTypeExact,
+				"webapp-books":    v1.PathTypeExact,
+				"webapp-books-id": v1.PathTypePrefix,
+			},
+		},
+	}
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /:
+    get: {}
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+			r.NoError(err)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Ingress: options.IngressOptions{
+					PathType: testCase.pathType,
+				},
+				PathSubOptions: testCase.pathSubOptions,
+			}
+			r.NoError(opts.FillDefaultsAndValidate())
+
+			ingresses, err := gen.splitPath(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, len(testCase.res))
+
+			for _, ingress := range ingresses {
+				r.Equal(testCase.res[ingress.Name], *ingress.Spec.Rules[0].HTTP.Paths[0].PathType, ingress.Name)
+			}
+		})
+	}
+}
+
+func TestInvalidPathType(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Ingress: options.IngressOptions{
+			PathType: "Regex",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestInvalidPathSubType(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		PathSubOptions: map[string]options.SubOptions{
+			"/books": {
+				Path: options.PathOptions{
+					Type: "Regex",
+				},
+			},
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestNamespaceOmittedWhenEmpty(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+	}
+
+	var gen Generator
+	res, err := gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.NotContains(res, "\n  namespace:")
+
+	opts.Namespace = "books"
+	res, err = gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Contains(res, "\n  namespace: books\n")
+}
+
+func TestWildcardHost(t *testing.T) {
+	testCases := []struct {
+		host     string
+		ruleHost string
+		tlsHosts []string
+		error    bool
+	}{
+		{host: "example.com", ruleHost: "example.com", tlsHosts: []string{"example.com"}},
+		{host: "*.example.com", ruleHost: "*.example.com", tlsHosts: []string{"*.example.com"}},
+		{host: "*.api.example.com", ruleHost: "*.api.example.com", tlsHosts: []string{"*.api.example.com"}},
+		// matching all the hosts, which Ingress rules do without a host
+		{host: "*", ruleHost: "", tlsHosts: nil},
+		{host: "*foo.com", error: true},
+		{host: "a.*.com", error: true},
+		{host: "*.*.example.com", error: true},
+		{host: "*.", error: true},
+		{host: "example..com", error: true},
+		{host: "-example.com", error: true},
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.host, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Host: testCase.host,
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Ingress: options.IngressOptions{
+					TLS: options.IngressTLSOptions{
+						SecretName: "webapp-tls",
+					},
+				},
+			}
+
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Len(ingresses, 1)
+			r.Equal(testCase.ruleHost, ingresses[0].Spec.Rules[0].Host)
+			r.Equal(testCase.tlsHosts, ingresses[0].Spec.TLS[0].Hosts)
+		})
+	}
+}
+
+func TestInvalidNamespace(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Namespace: "Not A Namespace",
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestCanary(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Host: "example.org",
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      8080,
+			Canary: options.CanaryOptions{
+				Name:   "webapp-v2",
+				Weight: 20,
+			},
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(&opts, apiSpec)
+	r.NoError(err)
+
+	names := make([]string, 0, len(ingresses))
+	for _, ingress := range ingresses {
+		names = append(names, ingress.Name)
+	}
+	r.Equal([]string{"webapp-books", "webapp-books-canary", "webapp-books-id", "webapp-books-id-canary"}, names)
+
+	for i := 0; i < len(ingresses); i += 2 {
+		main, canary := ingresses[i], ingresses[i+1]
+
+		r.NotContains(main.Annotations, canaryAnnotationKey)
+		r.Equal("webapp", main.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+
+		r.Equal("true", canary.Annotations[canaryAnnotationKey])
+		r.Equal("20", canary.Annotations[canaryWeightAnnotationKey])
+		r.Equal(main.Annotations[rewriteTargetAnnotationKey], canary.Annotations[rewriteTargetAnnotationKey])
+		r.Equal(main.Spec.Rules[0].Host, canary.Spec.Rules[0].Host)
+		r.Equal(main.Spec.Rules[0].HTTP.Paths[0].Path, canary.Spec.Rules[0].HTTP.Paths[0].Path)
+		r.Equal("webapp-v2", canary.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+		// the canary port defaults to the target Service port
+		r.Equal(int32(8080), canary.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+	}
+}
+
+func TestDefaultBackend(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		defaultBackend bool
+		canary         bool
+	}{
+		{
+			name: "omitted by default",
+		},
+		{
+			name:           "set when requested",
+			defaultBackend: true,
+		},
+		{
+			name:           "kept off the canary Ingress",
+			defaultBackend: true,
+			canary:         true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      8080,
+				},
+				Ingress: options.IngressOptions{
+					DefaultBackend: testCase.defaultBackend,
+				},
+			}
+			if testCase.canary {
+				opts.Service.Canary = options.CanaryOptions{Name: "webapp-v2", Weight: 10}
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+
+			main := ingresses[0]
+			if !testCase.defaultBackend {
+				r.Nil(main.Spec.DefaultBackend)
+				return
+			}
+
+			r.NotNil(main.Spec.DefaultBackend)
+			r.Equal("webapp", main.Spec.DefaultBackend.Service.Name)
+			r.Equal(int32(8080), main.Spec.DefaultBackend.Service.Port.Number)
+
+			if testCase.canary {
+				r.Len(ingresses, 2)
+				r.Nil(ingresses[1].Spec.DefaultBackend)
+			}
+		})
+	}
+}
+
+func TestServicePort(t *testing.T) {
+	testCases := []struct {
+		name     string
+		port     int32
+		portName string
+		res      v1.ServiceBackendPort
+		error    bool
+	}{
+		{
+			name: "default port",
+			res:  v1.ServiceBackendPort{Number: 80},
+		},
+		{
+			name: "port",
+			port: 8080,
+			res:  v1.ServiceBackendPort{Number: 8080},
+		},
+		{
+			name:     "port name",
+			portName: "http",
+			res:      v1.ServiceBackendPort{Name: "http"},
+		},
+		{
+			name:     "port and port name",
+			port:     8080,
+			portName: "http",
+			error:    true,
+		},
+		{
+			name:  "port out of range",
+			port:  65536,
+			error: true,
+		},
+		{
+			name:  "negative port",
+			port:  -1,
+			error: true,
+		},
+		{
+			name:     "invalid port name",
+			portName: "HTTP_PORT",
+			error:    true,
+		},
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      testCase.port,
+					PortName:  testCase.portName,
+					Canary: options.CanaryOptions{
+						Name: "webapp-v2",
+					},
+				},
+			}
+
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Len(ingresses, 2)
+			// the canary Service port defaults to the target Service port
+			for _, ingress := range ingresses {
+				r.Equal(testCase.res, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port)
+			}
+		})
+	}
+}
+
+func TestServicePortNameOutput(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	for _, split := range []bool{false, true} {
+		split := split
+
+		t.Run(fmt.Sprintf("split %t", split), func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					PortName:  "http",
+				},
+				Path: options.PathOptions{
+					Split: split,
+				},
+			}
+
+			var gen Generator
+			res, err := gen.Generate(&opts, apiSpec)
+			r.NoError(err)
+
+			r.Contains(res, `
+            port:
+              name: http
+`)
+			r.NotContains(res, "number:")
+		})
+	}
+}
+
+func TestBackendProtocol(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	for _, protocol := range []string{"", "HTTP", "HTTPS", "GRPC", "GRPCS"} {
+		protocol := protocol
+
+		t.Run(fmt.Sprintf("protocol %q", protocol), func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+					Protocol:  protocol,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			if protocol == "" {
+				// left to the controller default
+				r.NotContains(ingresses[0].Annotations, backendProtocolAnnotationKey)
+				return
+			}
+
+			r.Equal(protocol, ingresses[0].Annotations[backendProtocolAnnotationKey])
+		})
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      string
+		protocols map[string]string
+		pathTypes map[string]v1.PathType
+	}{
+		{
+			name: "global",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Greeter
+  version: 1.0.0
+x-kusk:
+  grpc: true
+paths:
+  /helloworld.Greeter/{method}:
+    post: {}
+`,
+			protocols: map[string]string{"webapp-ingress": "GRPC"},
+			pathTypes: map[string]v1.PathType{"webapp-ingress": pathTypePrefix},
+		},
+		{
+			name: "path level",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Greeter
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /helloworld.Greeter/{method}:
+    x-kusk:
+      grpc: true
+    post: {}
+`,
+			protocols: map[string]string{
+				"webapp-books":                     "",
+				"webapp-helloworld-greeter-method": "GRPC",
+			},
+			pathTypes: map[string]v1.PathType{
+				"webapp-books":                     pathTypeExact,
+				"webapp-helloworld-greeter-method": pathTypePrefix,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err)
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err)
+
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, len(testCase.protocols))
+
+			for _, ingress := range ingresses {
+				r.Equal(testCase.protocols[ingress.Name], ingress.Annotations[backendProtocolAnnotationKey], ingress.Name)
+				r.Equal(testCase.pathTypes[ingress.Name], *ingress.Spec.Rules[0].HTTP.Paths[0].PathType, ingress.Name)
+			}
+		})
+	}
+}
+
+func TestInvalidBackendProtocol(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+			Protocol:  "https",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestInvalidCanaryWeight(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Canary: options.CanaryOptions{
+				Name:   "webapp-v2",
+				Weight: 120,
+			},
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestSSLRedirect(t *testing.T) {
+	trueValue := true
+	falseValue := false
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		tlsSecret        string
+		sslRedirect      *bool
+		forceSSLRedirect bool
+		expected         map[string]string
+	}{
+		{
+			name:     "TLS off",
+			expected: map[string]string{},
+		},
+		{
+			name:      "TLS on",
+			tlsSecret: "webapp-tls",
+			expected: map[string]string{
+				sslRedirectAnnotationKey: "true",
+			},
+		},
+		{
+			name:        "TLS on, redirect disabled",
+			tlsSecret:   "webapp-tls",
+			sslRedirect: &falseValue,
+			expected: map[string]string{
+				sslRedirectAnnotationKey: "false",
+			},
+		},
+		{
+			name:        "TLS off, redirect enabled",
+			sslRedirect: &trueValue,
+			expected: map[string]string{
+				sslRedirectAnnotationKey: "true",
+			},
+		},
+		{
+			name:             "TLS off, forced redirect",
+			forceSSLRedirect: true,
+			expected: map[string]string{
+				forceSSLRedirectAnnotationKey: "true",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Ingress: options.IngressOptions{
+					TLS: options.IngressTLSOptions{
+						SecretName: testCase.tlsSecret,
+					},
+					SSLRedirect:      testCase.sslRedirect,
+					ForceSSLRedirect: testCase.forceSSLRedirect,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			actual := map[string]string{}
+			for _, key := range []string{sslRedirectAnnotationKey, forceSSLRedirectAnnotationKey} {
+				if value, ok := ingresses[0].Annotations[key]; ok {
+					actual[key] = value
+				}
+			}
+			r.Equal(testCase.expected, actual)
+			r.Equal(testCase.tlsSecret != "", len(ingresses[0].Spec.TLS) > 0)
+		})
+	}
+}
+
+func TestPathSSLRedirect(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  ingress:
+    ssl_redirect: true
+paths:
+  /.well-known/acme-challenge/{token}:
+    x-kusk:
+      ingress:
+        ssl_redirect: false
+    get: {}
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+
+	// the path-level override requires a separate Ingress for each path
+	r.Len(ingresses, 2)
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.Equal("true", ingresses[0].Annotations[sslRedirectAnnotationKey])
+	r.Equal("webapp-well-known-acme-challenge-token", ingresses[1].Name)
+	r.Equal("false", ingresses[1].Annotations[sslRedirectAnnotationKey])
+}
+
+func TestProxyBodySize(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  ingress:
+    proxy_body_size: 1m
+paths:
+  /books:
+    get: {}
+  /uploads:
+    x-kusk:
+      ingress:
+        proxy_body_size: 100m
+    post: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+
+	// the path-level override requires a separate Ingress for each path
+	r.Len(ingresses, 2)
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.Equal("1m", ingresses[0].Annotations[proxyBodySizeAnnotationKey])
+	r.Equal("webapp-uploads", ingresses[1].Name)
+	r.Equal("100m", ingresses[1].Annotations[proxyBodySizeAnnotationKey])
+}
+
+func TestPathMethods(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /authors/{id}:
+    get: {}
+    delete: {}
+  /books:
+    get: {}
+    post: {}
+  /orders:
+    post: {}
+  /status:
+    head: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Path: options.PathOptions{
+			Methods: []string{"GET"},
+		},
+		Ingress: options.IngressOptions{
+			RestrictMethods: true,
+		},
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(&opts, apiSpec)
+	r.NoError(err)
+
+	// the paths without a GET operation are left out, which requires a separate Ingress for each path
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-authors-id", ingresses[0].Name)
+	r.Equal(generateLimitExceptSnippet([]string{"GET"}), ingresses[0].Annotations[configurationSnippetAnnotationKey])
+
+	r.Equal("webapp-books", ingresses[1].Name)
+	r.Equal(generateLimitExceptSnippet([]string{"GET"}), ingresses[1].Annotations[configurationSnippetAnnotationKey])
+}
+
+func TestPathIngressClass(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  ingress:
+    class: nginx-public
+paths:
+  /admin:
+    x-kusk:
+      ingress:
+        class: nginx-internal
+    get: {}
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+
+	// the path-level class requires a separate Ingress for each path
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-admin", ingresses[0].Name)
+	r.Equal("nginx-internal", *ingresses[0].Spec.IngressClassName)
+
+	r.Equal("webapp-books", ingresses[1].Name)
+	r.Equal("nginx-public", *ingresses[1].Spec.IngressClassName)
+}
+
+func TestInvalidIngressClass(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		PathSubOptions: map[string]options.SubOptions{
+			"/admin": {
+				Ingress: options.IngressOptions{
+					Class: "Internal_Nginx",
+				},
+			},
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestWebsocket(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /ws:
+    x-kusk:
+      ingress:
+        websocket: true
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+
+	// the path-level websocket support requires a separate Ingress for each path
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.NotContains(ingresses[0].Annotations, proxyReadTimeoutAnnotationKey)
+	r.NotContains(ingresses[0].Annotations, proxySendTimeoutAnnotationKey)
+
+	r.Equal("webapp-ws", ingresses[1].Name)
+	r.Equal(websocketTimeout, ingresses[1].Annotations[proxyReadTimeoutAnnotationKey])
+	r.Equal(websocketTimeout, ingresses[1].Annotations[proxySendTimeoutAnnotationKey])
+}
+
+func TestProxyBuffering(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /events:
+    x-kusk:
+      ingress:
+        proxy_buffering: "off"
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+	opts.Path.Split = true
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.NotContains(ingresses[0].Annotations, proxyBufferingAnnotationKey)
+
+	r.Equal("webapp-events", ingresses[1].Name)
+	r.Equal("off", ingresses[1].Annotations[proxyBufferingAnnotationKey])
+}
+
+func TestInvalidProxyBuffering(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts options.Options
+	}{
+		{
+			name: "global",
+			opts: options.Options{
+				Ingress: options.IngressOptions{
+					ProxyBuffering: "false",
+				},
+			},
+		},
+		{
+			name: "path level",
+			opts: options.Options{
+				PathSubOptions: map[string]options.SubOptions{
+					"/events": {
+						Ingress: options.IngressOptions{
+							ProxyBuffering: "disabled",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := testCase.opts
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), "ingress.proxy_buffering must be one of on or off")
+		})
+	}
+}
+
+func TestPathTimeouts(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  timeouts:
+    request_timeout: 30
+paths:
+  /books:
+    get: {}
+  /reports:
+    x-kusk:
+      timeouts:
+        request_timeout: 300
+    post: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.Equal("15", ingresses[0].Annotations[proxyReadTimeoutAnnotationKey])
+	r.Equal("15", ingresses[0].Annotations[proxySendTimeoutAnnotationKey])
+
+	r.Equal("webapp-reports", ingresses[1].Name)
+	r.Equal("150", ingresses[1].Annotations[proxyReadTimeoutAnnotationKey])
+	r.Equal("150", ingresses[1].Annotations[proxySendTimeoutAnnotationKey])
+}
+
+func TestInvalidTimeouts(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  options.Options
+		error string
+	}{
+		{
+			name: "global",
+			opts: options.Options{
+				Timeouts: options.TimeoutOptions{
+					RequestTimeout: 300000,
+				},
+			},
+			error: "timeouts.request_timeout must be at most 86400 seconds",
+		},
+		{
+			name: "path level",
+			opts: options.Options{
+				PathSubOptions: map[string]options.SubOptions{
+					"/reports": {
+						Timeouts: options.TimeoutOptions{
+							IdleTimeout: 600000,
+						},
+					},
+				},
+			},
+			error: "/reports: idle_timeout: timeouts.idle_timeout must be at most 86400 seconds",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := testCase.opts
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), testCase.error)
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  ingress:
+    request_headers:
+      X-Forwarded-Prefix: /books
+    response_headers:
+      X-Frame-Options: DENY
+      Strict-Transport-Security: max-age=31536000
+paths:
+  /books:
+    get: {}
+  /embed:
+    x-kusk:
+      ingress:
+        response_headers:
+          X-Frame-Options: SAMEORIGIN
+          Content-Security-Policy: frame-ancestors "self"
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+	opts.Path.Split = true
+	opts.Ingress.RestrictMethods = true
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.Equal(`limit_except GET {
+  deny all;
+}
+proxy_set_header X-Forwarded-Prefix "/books";
+more_set_headers "Strict-Transport-Security: max-age=31536000";
+more_set_headers "X-Frame-Options: DENY";
+`, ingresses[0].Annotations[configurationSnippetAnnotationKey])
+
+	r.Equal("webapp-embed", ingresses[1].Name)
+	r.Equal(`limit_except GET {
+  deny all;
+}
+proxy_set_header X-Forwarded-Prefix "/books";
+more_set_headers "Content-Security-Policy: frame-ancestors \"self\"";
+more_set_headers "Strict-Transport-Security: max-age=31536000";
+more_set_headers "X-Frame-Options: SAMEORIGIN";
+`, ingresses[1].Annotations[configurationSnippetAnnotationKey])
+}
+
+func TestInvalidHeaders(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  options.Options
+		error string
+	}{
+		{
+			name: "request header name",
+			opts: options.Options{
+				Ingress: options.IngressOptions{
+					RequestHeaders: map[string]string{"X Forwarded Prefix": "/books"},
+				},
+			},
+			error: `ingress.request_headers name "X Forwarded Prefix" is not a valid header name`,
+		},
+		{
+			name: "response header value",
+			opts: options.Options{
+				Ingress: options.IngressOptions{
+					ResponseHeaders: map[string]string{"X-Frame-Options": "DENY\nX-Injected: true"},
+				},
+			},
+			error: `ingress.response_headers value of "X-Frame-Options" must not contain line breaks`,
+		},
+		{
+			name: "path level",
+			opts: options.Options{
+				PathSubOptions: map[string]options.SubOptions{
+					"/embed": {
+						Ingress: options.IngressOptions{
+							ResponseHeaders: map[string]string{"X-Frame-Options:": "SAMEORIGIN"},
+						},
+					},
+				},
+			},
+			error: `ingress.response_headers name "X-Frame-Options:" is not a valid header name`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := testCase.opts
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), testCase.error)
+		})
+	}
+}
+
+func TestTrailingSlash(t *testing.T) {
+	type route struct {
+		path     string
+		pathType v1.PathType
+		snippet  string
+	}
+
+	testCases := []struct {
+		name        string
+		split       bool
+		pathType    string
+		appendSlash bool
+		stripSlash  bool
+		res         []route
+		warnings    string
+		error       string
+	}{
+		{
+			name: "unset",
+			res: []route{
+				{path: "/", pathType: pathTypePrefix},
+			},
+		},
+		{
+			name:        "append",
+			appendSlash: true,
+			res: []route{
+				{path: "/", pathType: pathTypePrefix, snippet: "rewrite ^(.*[^/])$ $1/ permanent;\n"},
+			},
+		},
+		{
+			name:       "strip",
+			stripSlash: true,
+			res: []route{
+				{path: "/", pathType: pathTypePrefix, snippet: "rewrite ^(.+)/$ $1 permanent;\n"},
+			},
+		},
+		{
+			name:        "append to split paths routing the redirected path only",
+			split:       true,
+			appendSlash: true,
+			res: []route{
+				{path: "/books", pathType: pathTypeExact},
+				{path: "/books/([^/]+)", pathType: pathTypeImplementationSpecific, snippet: "rewrite ^(.*[^/])$ $1/ permanent;\n"},
+			},
+			warnings: "WARNingress.append_slash is left out of the Exact /books path, which doesn't route the redirected path\n",
+		},
+		{
+			name:       "strip from split prefix paths",
+			split:      true,
+			pathType:   string(pathTypePrefix),
+			stripSlash: true,
+			res: []route{
+				{path: "/books", pathType: pathTypePrefix, snippet: "rewrite ^(.+)/$ $1 permanent;\n"},
+				{path: "/books/([^/]+)", pathType: pathTypePrefix, snippet: "rewrite ^(.+)/$ $1 permanent;\n"},
+			},
+		},
+		{
+			name:        "both",
+			appendSlash: true,
+			stripSlash:  true,
+			error:       "ingress.append_slash and ingress.strip_slash can't be set together",
+		},
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Split: testCase.split,
+				},
+				Ingress: options.IngressOptions{
+					PathType:    testCase.pathType,
+					AppendSlash: testCase.appendSlash,
+					StripSlash:  testCase.stripSlash,
+				},
+			}
+
+			var warnings bytes.Buffer
+			warnOutput = &warnings
+			defer func() { warnOutput = os.Stderr }()
+
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			if testCase.error != "" {
+				r.Error(err)
+				r.Contains(err.Error(), testCase.error)
+				return
+			}
+
+			r.NoError(err)
+
+			var res []route
+			for _, ingress := range ingresses {
+				for _, path := range ingress.Spec.Rules[0].HTTP.Paths {
+					res = append(res, route{
+						path:     path.Path,
+						pathType: *path.PathType,
+						snippet:  ingress.Annotations[configurationSnippetAnnotationKey],
+					})
+				}
+			}
+
+			r.Equal(testCase.res, res)
+			r.Equal(testCase.warnings, warnings.String())
+		})
+	}
+}
+
+func TestWhitelistSourceRange(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /admin:
+    x-kusk:
+      ingress:
+        whitelist_source_range:
+        - 203.0.113.0/24
+        - 198.51.100.7/32
+    get: {}
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+
+	// the path-level source range requires a separate Ingress for each path
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-admin", ingresses[0].Name)
+	r.Equal("203.0.113.0/24,198.51.100.7/32", ingresses[0].Annotations[whitelistSourceRangeAnnotationKey])
+
+	r.Equal("webapp-books", ingresses[1].Name)
+	r.NotContains(ingresses[1].Annotations, whitelistSourceRangeAnnotationKey)
+}
+
+func TestInvalidWhitelistSourceRange(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts options.Options
+	}{
+		{
+			name: "global",
+			opts: options.Options{
+				Ingress: options.IngressOptions{
+					WhitelistSourceRange: []string{"10.0.0.0/24", "10.0.0.300/32"},
+				},
+			},
+		},
+		{
+			name: "path level",
+			opts: options.Options{
+				PathSubOptions: map[string]options.SubOptions{
+					"/admin": {
+						Ingress: options.IngressOptions{
+							WhitelistSourceRange: []string{"10.0.0.1"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := testCase.opts
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), "must be a valid CIDR")
+		})
+	}
+}
+
+func TestInvalidProxyBodySize(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts options.Options
+	}{
+		{
+			name: "global",
+			opts: options.Options{
+				Ingress: options.IngressOptions{
+					ProxyBodySize: "8 MB",
+				},
+			},
+		},
+		{
+			name: "path level",
+			opts: options.Options{
+				PathSubOptions: map[string]options.SubOptions{
+					"/uploads": {
+						Ingress: options.IngressOptions{
+							ProxyBodySize: "-1",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := testCase.opts
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		basic    options.IngressBasicAuthOptions
+		expected map[string]string
+	}{
+		{
+			name:     "no auth by default",
+			expected: map[string]string{},
+		},
+		{
+			name: "secret and realm",
+			basic: options.IngressBasicAuthOptions{
+				Secret: "basic-auth",
+				Realm:  "Authentication Required",
+			},
+			expected: map[string]string{
+				authTypeAnnotationKey:   "basic",
+				authSecretAnnotationKey: "basic-auth",
+				authRealmAnnotationKey:  "Authentication Required",
+			},
+		},
+		{
+			name: "secret only",
+			basic: options.IngressBasicAuthOptions{
+				Secret: "auth/basic-auth",
+			},
+			expected: map[string]string{
+				authTypeAnnotationKey:   "basic",
+				authSecretAnnotationKey: "auth/basic-auth",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Ingress: options.IngressOptions{
+					Auth: options.IngressAuthOptions{
+						Basic: testCase.basic,
+					},
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			actual := map[string]string{}
+			for _, key := range []string{authTypeAnnotationKey, authSecretAnnotationKey, authRealmAnnotationKey} {
+				if value, ok := ingresses[0].Annotations[key]; ok {
+					actual[key] = value
+				}
+			}
+			r.Equal(testCase.expected, actual)
+		})
+	}
+}
+
+func TestForwardAuth(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Ingress: options.IngressOptions{
+			Auth: options.IngressAuthOptions{
+				URL:             "http://oauth2-proxy.auth.svc.cluster.local/oauth2/auth",
+				Signin:          "https://auth.example.org/oauth2/start?rd=$escaped_request_uri",
+				ResponseHeaders: []string{"X-Auth-Request-User", "X-Auth-Request-Email"},
+			},
+		},
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 1)
+
+	annotations := ingresses[0].Annotations
+	r.Equal("http://oauth2-proxy.auth.svc.cluster.local/oauth2/auth", annotations[authURLAnnotationKey])
+	r.Equal("https://auth.example.org/oauth2/start?rd=$escaped_request_uri", annotations[authSigninAnnotationKey])
+	r.Equal("X-Auth-Request-User,X-Auth-Request-Email", annotations[authResponseHeadersAnnotationKey])
+	r.NotContains(annotations, authTypeAnnotationKey)
+}
+
+func TestAuthFromSpec(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+    basicAuth:
+      type: http
+      scheme: basic
+paths:
+  /books:
+    get: {}
+  /orders:
+    get:
+      security:
+      - bearerAuth: []
+  /admin:
+    get:
+      security:
+      - basicAuth: []
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		auth        options.IngressAuthOptions
+		annotations map[string]map[string]string
+	}{
+		{
+			name: "forward auth",
+			auth: options.IngressAuthOptions{
+				URL:      "https://auth.example.com/verify",
+				FromSpec: true,
+			},
+			annotations: map[string]map[string]string{
+				"webapp-admin":  {authURLAnnotationKey: "https://auth.example.com/verify"},
+				"webapp-books":  {},
+				"webapp-orders": {authURLAnnotationKey: "https://auth.example.com/verify"},
+			},
+		},
+		{
+			name: "matching schemes",
+			auth: options.IngressAuthOptions{
+				URL: "https://auth.example.com/verify",
+				Basic: options.IngressBasicAuthOptions{
+					Secret: "admin-htpasswd",
+				},
+				FromSpec: true,
+			},
+			annotations: map[string]map[string]string{
+				"webapp-admin": {
+					authTypeAnnotationKey:   "basic",
+					authSecretAnnotationKey: "admin-htpasswd",
+				},
+				"webapp-books":  {},
+				"webapp-orders": {authURLAnnotationKey: "https://auth.example.com/verify"},
+			},
+		},
+		{
+			name: "schemes mapping",
+			auth: options.IngressAuthOptions{
+				URL: "https://auth.example.com/verify",
+				Basic: options.IngressBasicAuthOptions{
+					Secret: "admin-htpasswd",
+				},
+				FromSpec: true,
+				Schemes: map[string]string{
+					"basicAuth": options.AuthSchemeForward,
+				},
+			},
+			annotations: map[string]map[string]string{
+				"webapp-admin":  {authURLAnnotationKey: "https://auth.example.com/verify"},
+				"webapp-books":  {},
+				"webapp-orders": {authURLAnnotationKey: "https://auth.example.com/verify"},
+			},
+		},
+	}
+
+	authAnnotationKeys := []string{authTypeAnnotationKey, authSecretAnnotationKey, authURLAnnotationKey}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Ingress: options.IngressOptions{
+					Auth: testCase.auth,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+
+			// the paths without security requirement aren't protected, which requires a separate Ingress for each path
+			r.Len(ingresses, len(testCase.annotations))
+
+			for _, ingress := range ingresses {
+				expected, ok := testCase.annotations[ingress.Name]
+				r.True(ok, ingress.Name)
+
+				for _, key := range authAnnotationKeys {
+					if value, ok := expected[key]; ok {
+						r.Equal(value, ingress.Annotations[key], ingress.Name)
+					} else {
+						r.NotContains(ingress.Annotations, key, ingress.Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAuthFromSpecRequiresAuth(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Ingress: options.IngressOptions{
+			Auth: options.IngressAuthOptions{
+				FromSpec: true,
+			},
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestInvalidForwardAuthURL(t *testing.T) {
+	testCases := []struct {
+		name string
+		auth options.IngressAuthOptions
+	}{
+		{
+			name: "relative url",
+			auth: options.IngressAuthOptions{URL: "/oauth2/auth"},
+		},
+		{
+			name: "unsupported scheme",
+			auth: options.IngressAuthOptions{URL: "ftp://auth.example.org/"},
+		},
+		{
+			name: "malformed signin",
+			auth: options.IngressAuthOptions{
+				URL:    "http://oauth2-proxy/oauth2/auth",
+				Signin: "https://auth example.org/%zz",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Ingress: options.IngressOptions{
+					Auth: testCase.auth,
+				},
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestBasicAuthRealmRequiresSecret(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Ingress: options.IngressOptions{
+			Auth: options.IngressAuthOptions{
+				Basic: options.IngressBasicAuthOptions{
+					Realm: "Authentication Required",
+				},
+			},
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestLabels(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "managed-by by default",
+			expected: map[string]string{
+				"app.kubernetes.io/managed-by": "kusk",
+			},
+		},
+		{
+			name: "user labels",
+			labels: map[string]string{
+				"app.kubernetes.io/part-of": "books",
+				"team":                      "catalog",
+			},
+			expected: map[string]string{
+				"app.kubernetes.io/managed-by": "kusk",
+				"app.kubernetes.io/part-of":    "books",
+				"team":                         "catalog",
+			},
+		},
+		{
+			name: "managed-by overridden",
+			labels: map[string]string{
+				"app.kubernetes.io/managed-by": "argocd",
+			},
+			expected: map[string]string{
+				"app.kubernetes.io/managed-by": "argocd",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Ingress: options.IngressOptions{
+					Labels: testCase.labels,
+				},
+				NetworkPolicy: options.NetworkPolicyOptions{
+					Generate: true,
+				},
+			}
+
+			var gen Generator
+			resources, err := gen.GenerateResources(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(resources, 3)
+
+			for _, resource := range resources {
+				metadata, err := meta.Accessor(resource)
+				r.NoError(err)
+				r.Equal(testCase.expected, metadata.GetLabels(), metadata.GetName())
+			}
+		})
+	}
+}
+
+func TestInvalidLabels(t *testing.T) {
+	testCases := []struct {
+		name   string
+		labels map[string]string
+	}{
+		{
+			name:   "invalid key",
+			labels: map[string]string{"team/": "catalog"},
+		},
+		{
+			name:   "invalid value",
+			labels: map[string]string{"team": "books catalog"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Ingress: options.IngressOptions{
+					Labels: testCase.labels,
+				},
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+		})
+	}
+}
+
+func TestDedupeNames(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /a-b:
+    get: {}
+  /a/b:
+    get: {}
+  /a_b:
+    get: {}
+  /c:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+	}
+
+	t.Run("collision is an error", func(t *testing.T) {
+		r := require.New(t)
+
+		var gen Generator
+		_, err := gen.Generate(&opts, apiSpec)
+		r.EqualError(err, "/a-b and /a/b would both generate an Ingress named webapp-a-b, use ingress.dedupe_names to disambiguate them")
+	})
+
+	t.Run("dedupe names", func(t *testing.T) {
+		r := require.New(t)
+
+		opts := opts
+		opts.Ingress.DedupeNames = true
+
+		var gen Generator
+		ingresses, err := gen.generateIngresses(&opts, apiSpec)
+		r.NoError(err)
+
+		paths := map[string]string{}
+		for _, ingress := range ingresses {
+			paths[ingress.Name] = ingress.Spec.Rules[0].HTTP.Paths[0].Path
+		}
+
+		r.Equal(map[string]string{
+			"webapp-a-b":   "/a-b",
+			"webapp-a-b-2": "/a/b",
+			"webapp-a-b-3": "/a_b",
+			"webapp-c":     "/c",
+		}, paths)
+	})
+}
+
+func TestCollapseQueryPaths(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /search?type=authors:
+    get: {}
+  /search?type=books:
+    post: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+		Ingress: options.IngressOptions{
+			RestrictMethods: true,
+		},
+	}
+
+	var warnings bytes.Buffer
+	warnOutput = &warnings
+	defer func() { warnOutput = os.Stderr }()
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(&opts, apiSpec)
+	r.NoError(err)
+
+	r.Len(ingresses, 1)
+	r.Equal("webapp-search", ingresses[0].Name)
+	r.Equal("/search", ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+	// the single Ingress serves the methods of both paths
+	r.Equal(generateLimitExceptSnippet([]string{"GET", "POST"}), ingresses[0].Annotations[configurationSnippetAnnotationKey])
+
+	r.Equal(
+		"WARN/search?type=authors and /search?type=books only differ by their query string which Ingress can't route on, a single Ingress is generated for /search\n",
+		warnings.String(),
+	)
+}
+
+func TestIngressAPIVersion(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		apiVersion string
+		res        string
+	}{
+		{
+			name: "v1 by default",
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+spec:
+  defaultBackend:
+    service:
+      name: webapp
+      port:
+        number: 7000
+  ingressClassName: nginx
+  rules:
+  - host: example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /
+        pathType: Prefix
+  tls:
+  - hosts:
+    - example.org
+    secretName: example-tls
+`,
+		},
+		{
+			name:       "v1beta1",
+			apiVersion: "networking.k8s.io/v1beta1",
+			res: `---
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+spec:
+  backend:
+    serviceName: webapp
+    servicePort: 7000
+  ingressClassName: nginx
+  rules:
+  - host: example.org
+    http:
+      paths:
+      - backend:
+          serviceName: webapp
+          servicePort: 7000
+        path: /
+        pathType: Prefix
+  tls:
+  - hosts:
+    - example.org
+    secretName: example-tls
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Host: "example.org",
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      7000,
+				},
+				Ingress: options.IngressOptions{
+					APIVersion:     testCase.apiVersion,
+					DefaultBackend: true,
+					TLS: options.IngressTLSOptions{
+						SecretName: "example-tls",
+					},
+				},
+			}
+
+			var gen Generator
+			res, err := gen.Generate(&opts, apiSpec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestInvalidIngressAPIVersion(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Ingress: options.IngressOptions{
+			APIVersion: "extensions/v1beta1",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestSessionAffinity(t *testing.T) {
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		affinity options.IngressAffinityOptions
+		expected map[string]string
+	}{
+		{
+			name:     "no affinity by default",
+			expected: map[string]string{},
+		},
+		{
+			name: "cookie",
+			affinity: options.IngressAffinityOptions{
+				Type: "cookie",
+				Cookie: options.IngressAffinityCookieOptions{
+					Name: "route",
+				},
+			},
+			expected: map[string]string{
+				affinityAnnotationKey:          "cookie",
+				sessionCookieNameAnnotationKey: "route",
+			},
+		},
+		{
+			name: "cookie with expiration",
+			affinity: options.IngressAffinityOptions{
+				Type: "cookie",
+				Cookie: options.IngressAffinityCookieOptions{
+					Name:    "route",
+					Expires: 172800,
+				},
+			},
+			expected: map[string]string{
+				affinityAnnotationKey:             "cookie",
+				sessionCookieNameAnnotationKey:    "route",
+				sessionCookieExpiresAnnotationKey: "172800",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Ingress: options.IngressOptions{
+					Affinity: testCase.affinity,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+			r.Equal(testCase.expected, ingresses[0].Annotations)
+		})
+	}
+}
+
+func TestInvalidSessionAffinity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		affinity options.IngressAffinityOptions
+	}{
+		{
+			name: "cookie name required",
+			affinity: options.IngressAffinityOptions{
+				Type: "cookie",
+			},
+		},
+		{
+			name: "unsupported type",
+			affinity: options.IngressAffinityOptions{
+				Type: "ip",
+				Cookie: options.IngressAffinityCookieOptions{
+					Name: "route",
+				},
+			},
+		},
+		{
+			name: "negative expiration",
+			affinity: options.IngressAffinityOptions{
+				Type: "cookie",
+				Cookie: options.IngressAffinityCookieOptions{
+					Name:    "route",
+					Expires: -1,
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Ingress: options.IngressOptions{
+					Affinity: testCase.affinity,
+				},
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+		})
+	}
+}
+
+func TestInvalidCORSCredentials(t *testing.T) {
+	trueValue := true
+	cors := options.CORSOptions{
+		Origins:     []string{"http://foo.example", "*"},
+		Credentials: &trueValue,
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(o *options.Options)
+	}{
+		{
+			name:   "global",
+			modify: func(o *options.Options) { o.CORS = cors },
+		},
+		{
+			name: "path",
+			modify: func(o *options.Options) {
+				o.PathSubOptions = map[string]options.SubOptions{
+					"/books": {CORS: cors},
+				}
+			},
+		},
+		{
+			name: "operation",
+			modify: func(o *options.Options) {
+				o.OperationSubOptions = map[string]options.SubOptions{
+					"GET/books": {CORS: cors},
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+			}
+			testCase.modify(&opts)
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), "cors.origins can't contain * when cors.credentials is enabled")
+		})
+	}
+}
+
+func TestGRPCWebCORS(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cors        options.CORSOptions
+		annotations map[string]string
+	}{
+		{
+			name: "defaults",
+			cors: options.CORSOptions{
+				Origins: []string{"https://app.example.com"},
+				GRPCWeb: true,
+			},
+			annotations: map[string]string{
+				corsEnableAnnotationKey:                          "true",
+				"nginx.ingress.kubernetes.io/cors-allow-origin":  "https://app.example.com",
+				"nginx.ingress.kubernetes.io/cors-allow-methods": "POST, OPTIONS",
+				"nginx.ingress.kubernetes.io/cors-allow-headers": "keep-alive, user-agent, cache-control, content-type, " +
+					"content-transfer-encoding, x-accept-content-transfer-encoding, x-accept-response-streaming, " +
+					"x-user-agent, x-grpc-web, grpc-timeout",
+				"nginx.ingress.kubernetes.io/cors-expose-headers": "grpc-status, grpc-message, grpc-status-details-bin",
+				"nginx.ingress.kubernetes.io/cors-max-age":        "1728000",
+			},
+		},
+		{
+			name: "explicit options are kept",
+			cors: options.CORSOptions{
+				Origins: []string{"https://app.example.com"},
+				Headers: []string{"content-type", "x-grpc-web", "authorization"},
+				MaxAge:  600,
+				GRPCWeb: true,
+			},
+			annotations: map[string]string{
+				corsEnableAnnotationKey:                           "true",
+				"nginx.ingress.kubernetes.io/cors-allow-origin":   "https://app.example.com",
+				"nginx.ingress.kubernetes.io/cors-allow-methods":  "POST, OPTIONS",
+				"nginx.ingress.kubernetes.io/cors-allow-headers":  "content-type, x-grpc-web, authorization",
+				"nginx.ingress.kubernetes.io/cors-expose-headers": "grpc-status, grpc-message, grpc-status-details-bin",
+				"nginx.ingress.kubernetes.io/cors-max-age":        "600",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				CORS: testCase.cors,
+			}
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(singlePathSpec))
+			r.NoError(err)
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			for key, value := range testCase.annotations {
+				r.Equal(value, ingresses[0].Annotations[key], key)
+			}
+		})
+	}
+}
+
+func TestGenerateResources(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+		NetworkPolicy: options.NetworkPolicyOptions{
+			Generate: true,
+		},
+	}
+
+	var gen Generator
+	resources, err := gen.GenerateResources(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(resources, 2)
+
+	ingress, ok := resources[0].(*v1.Ingress)
+	r.True(ok)
+	r.Equal("webapp-ingress", ingress.Name)
+
+	_, ok = resources[1].(*v1.NetworkPolicy)
+	r.True(ok)
+
+	// Generate marshals the same resources
+	expected, err := generators.MarshalResources(resources)
+	r.NoError(err)
+
+	actual, err := gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Equal(expected, actual)
+
+	// resources can be mutated before marshalling
+	ingress.Labels = map[string]string{"team": "books"}
+
+	mutated, err := generators.MarshalResources(resources)
+	r.NoError(err)
+	r.Contains(mutated, "team: books")
+}
+
+func TestGenerateFiles(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /authors:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	r.NoError(err)
+
+	var gen Generator
+	files, err := gen.GenerateFiles(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(files, 2)
+	r.Contains(files["webapp-authors.yaml"], "name: webapp-authors\n")
+	r.Contains(files["webapp-books-id.yaml"], "name: webapp-books-id\n")
+
+	// /books/{id} and /books/id result in the same Ingress name
+	apiSpec, err = spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books/id:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	r.NoError(err)
+
+	_, err = gen.GenerateFiles(&opts, apiSpec)
+	r.Error(err)
+}
+
+func TestNetworkPolicy(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Namespace: "default",
+		Service: options.ServiceOptions{
+			Namespace: "books",
+			Name:      "webapp",
+			Port:      7000,
+		},
+		NetworkPolicy: options.NetworkPolicyOptions{
+			Generate:            true,
+			PodSelector:         map[string]string{"app.kubernetes.io/name": "booksapp"},
+			ControllerNamespace: "ingress",
+		},
+	}
+
+	var gen Generator
+	res, err := gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /
+        pathType: Prefix
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: books
+spec:
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: ingress
+    ports:
+    - port: 7000
+      protocol: TCP
+  podSelector:
+    matchLabels:
+      app.kubernetes.io/name: booksapp
+  policyTypes:
+  - Ingress
+`, res)
+
+	files, err := gen.GenerateFiles(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(files, 2)
+	r.Contains(files["webapp-network-policy.yaml"], "kind: NetworkPolicy\n")
+
+	// the default pod selector is derived from the Service name
+	opts.NetworkPolicy.PodSelector = nil
+	res, err = gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Contains(res, "  podSelector:\n    matchLabels:\n      app: webapp\n")
+}
+
+func TestGenerateService(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Namespace: "default",
+		Service: options.ServiceOptions{
+			Namespace: "books",
+			Name:      "webapp",
+			Port:      7000,
+			Generate:  true,
+			Selector:  map[string]string{"app.kubernetes.io/name": "booksapp"},
+		},
+	}
+
+	var gen Generator
+	res, err := gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /
+        pathType: Prefix
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp
+  namespace: books
+spec:
+  ports:
+  - name: http
+    port: 7000
+    protocol: TCP
+    targetPort: 7000
+  selector:
+    app.kubernetes.io/name: booksapp
+  type: ClusterIP
+`, res)
+
+	files, err := gen.GenerateFiles(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(files, 2)
+	r.Contains(files["webapp-service.yaml"], "kind: Service\n")
+}
+
+func TestGenerateMock(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Namespace: "default",
+		Service: options.ServiceOptions{
+			Namespace: "books",
+			Name:      "webapp",
+			Port:      7000,
+		},
+		Mock: options.MockOptions{
+			Generate: true,
+		},
+	}
+
+	var gen Generator
+	res, err := gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /
+        pathType: Prefix
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp
+  namespace: books
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: webapp
+  strategy: {}
+  template:
+    metadata:
+      labels:
+        app: webapp
+        app.kubernetes.io/managed-by: kusk
+    spec:
+      containers:
+      - args:
+        - -listen=:5678
+        - -text=webapp
+        image: hashicorp/http-echo
+        name: mock
+        ports:
+        - containerPort: 5678
+          name: http
+          protocol: TCP
+        resources: {}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kusk
+  name: webapp
+  namespace: books
+spec:
+  ports:
+  - name: http
+    port: 7000
+    protocol: TCP
+    targetPort: http
+  selector:
+    app: webapp
+  type: ClusterIP
+`, res)
+
+	files, err := gen.GenerateFiles(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(files, 2)
+	r.Contains(files["webapp-mock.yaml"], "kind: Deployment\n")
+	r.Contains(files["webapp-mock.yaml"], "kind: Service\n")
+
+	// a custom image gets no http-echo arguments
+	opts.Mock.Image = "ealen/echo-server"
+	opts.Mock.Port = 80
+	res, err = gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+	r.Contains(res, "      containers:\n      - image: ealen/echo-server\n")
+	r.Contains(res, "        - containerPort: 80\n")
+}
+
+func TestGenerateMockInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		service options.ServiceOptions
+		mock    options.MockOptions
+		error   string
+	}{
+		{
+			name: "service generate",
+			service: options.ServiceOptions{
+				Generate: true,
+				Selector: map[string]string{"app": "webapp"},
+			},
+			mock:  options.MockOptions{Generate: true},
+			error: "mock.generate and service.generate both generate the upstream Service",
+		},
+		{
+			name:    "port name",
+			service: options.ServiceOptions{PortName: "http"},
+			mock:    options.MockOptions{Generate: true},
+			error:   "service.port_name can't be used with mock.generate",
+		},
+		{
+			name:  "port",
+			mock:  options.MockOptions{Generate: true, Port: 70000},
+			error: "mock.port must be between 1 and 65535",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: testCase.service,
+				Mock:    testCase.mock,
+			}
+			opts.Service.Namespace = "default"
+			opts.Service.Name = "webapp"
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), testCase.error)
+		})
+	}
+}
+
+func TestGenerateServiceInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		service options.ServiceOptions
+	}{
+		{
+			name: "selector required",
+			service: options.ServiceOptions{
+				Port:     80,
+				Generate: true,
+			},
+		},
+		{
+			name: "port name",
+			service: options.ServiceOptions{
+				PortName: "http",
+				Generate: true,
+				Selector: map[string]string{"app": "webapp"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: testCase.service,
+			}
+			opts.Service.Namespace = "default"
+			opts.Service.Name = "webapp"
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+		})
+	}
+}
+
+func TestJSONListRoundTrip(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Namespace: "default",
+		Host:      "example.org",
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+	}
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(&opts, apiSpec)
+	r.NoError(err)
+
+	res, err := gen.Generate(&opts, apiSpec)
+	r.NoError(err)
+
+	list, err := generators.ToJSONList(res)
+	r.NoError(err)
+
+	var l struct {
+		Kind  string       `json:"kind"`
+		Items []v1.Ingress `json:"items"`
+	}
+	r.NoError(json.Unmarshal([]byte(list), &l))
+	r.Equal("List", l.Kind)
+	r.Equal(ingresses, l.Items)
+}
+
+func TestIngressResourceName(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+		res  string
+	}{
+		{
+			name: "root",
+			path: "/",
+			res:  "root",
+		},
+		{
+			name: "path variable",
+			path: "/books/{id}",
+			res:  "books-id",
+		},
+		{
+			name: "dots, underscores and uppercase",
+			path: "/api/v2.0/Users_List/{userId}",
+			res:  "api-v2-0-users-list-userid",
+		},
+		{
+			name: "consecutive and trailing invalid characters",
+			path: "/api//__internal__/",
+			res:  "api-internal",
+		},
+		{
+			name: "too long",
+			path: "/" + strings.Repeat("segment/", 10),
+			res:  "segment-segment-segment-segment-segment-segment-segmen-106893aa",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			name := ingressResourceNameFromPath(testCase.path)
+			r.Equal(testCase.res, name)
+			r.LessOrEqual(len(name), 63)
+			r.Regexp(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`, name)
+		})
+	}
+}
+
+func TestSplitPathBase(t *testing.T) {
+	testCases := []struct {
+		base    string
+		path    string
+		res     string
+		rewrite string
+	}{
+		{
+			base:    "/",
+			path:    "/",
+			res:     "/$",
+			rewrite: "/",
+		},
+		{
+			base:    "/",
+			path:    "/users",
+			res:     "/users",
+			rewrite: "/users",
+		},
+		{
+			base:    "/",
+			path:    "/users/",
+			res:     "/users/",
+			rewrite: "/users/",
+		},
+		{
+			base:    "/api",
+			path:    "/",
+			res:     "/api$",
+			rewrite: "/api/",
+		},
+		{
+			base:    "/api",
+			path:    "/users",
+			res:     "/api/users",
+			rewrite: "/api/users",
+		},
+		{
+			base:    "/api",
+			path:    "/users/",
+			res:     "/api/users/",
+			rewrite: "/api/users/",
+		},
+		{
+			base:    "/api/",
+			path:    "/",
+			res:     "/api$",
+			rewrite: "/api/",
+		},
+		{
+			base:    "/api/",
+			path:    "/users",
+			res:     "/api/users",
+			rewrite: "/api/users",
+		},
+		{
+			base:    "/api/",
+			path:    "/users/",
+			res:     "/api/users/",
+			rewrite: "/api/users/",
+		},
+	}
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.base+" "+testCase.path, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Base: testCase.base,
+				},
+			}
+			apiSpec := &openapi3.T{
+				Paths: openapi3.Paths{
+					testCase.path: &openapi3.PathItem{Get: &openapi3.Operation{}},
+				},
+			}
+
+			ingresses, err := gen.splitPath(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+			r.Equal(testCase.res, ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+			r.Equal(testCase.rewrite, ingresses[0].Annotations[rewriteTargetAnnotationKey])
+		})
+	}
+}
+
+func TestBasePathFromServers(t *testing.T) {
+	testCases := []struct {
+		name string
+		base string
+		res  string
+	}{
+		{
+			name: "derived from servers",
+			res:  "/v2/books",
+		},
+		{
+			name: "explicit path.base",
+			base: "/api",
+			res:  "/api/books",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+servers:
+  - url: https://api.example.com/v2
+paths:
+  /books:
+    get: {}
+`))
+			r.NoError(err)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Base:  testCase.base,
+					Split: true,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+			r.Equal(testCase.res, ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+		})
+	}
+}
+
+func TestIngressController(t *testing.T) {
+	testCases := []struct {
+		name        string
+		controller  string
+		annotations map[string]string
+		className   string
+	}{
+		{
+			name: "nginx by default",
+			annotations: map[string]string{
+				proxyReadTimeoutAnnotationKey: "15",
+				proxySendTimeoutAnnotationKey: "15",
+			},
+			className: "nginx",
+		},
+		{
+			name:       "haproxy",
+			controller: options.IngressControllerHAProxy,
+			annotations: map[string]string{
+				haproxyTimeoutServerAnnotationKey: "30s",
+			},
+			className: "haproxy",
+		},
+		{
+			name:        "traefik",
+			controller:  options.IngressControllerTraefik,
+			annotations: map[string]string{},
+			className:   "traefik",
+		},
+		{
+			name:        "alb",
+			controller:  options.IngressControllerALB,
+			annotations: map[string]string{},
+			className:   "alb",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(singlePathSpec))
+			r.NoError(err)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Timeouts: options.TimeoutOptions{
+					RequestTimeout: 30,
+				},
+				Ingress: options.IngressOptions{
+					Controller: testCase.controller,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			r.Equal(testCase.annotations, ingresses[0].Annotations)
+			r.Equal(testCase.className, *ingresses[0].Spec.IngressClassName)
+		})
+	}
+}
+
+func TestIngressControllerNGINXOnlyAnnotations(t *testing.T) {
+	testCases := []struct {
+		name        string
+		controller  string
+		annotations map[string]string
+		warnings    string
+	}{
+		{
+			name: "nginx",
+			annotations: map[string]string{
+				rewriteTargetAnnotationKey:                              "/books/$1",
+				useRegexAnnotationKey:                                   "true",
+				configurationSnippetAnnotationKey:                       "proxy_set_header X-Source \"kusk\";\n",
+				"nginx.ingress.kubernetes.io/proxy-next-upstream-tries": "3",
+				"nginx.ingress.kubernetes.io/server-snippet":            "keepalive_timeout 10s;",
+			},
+		},
+		{
+			name:       "haproxy",
+			controller: options.IngressControllerHAProxy,
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/server-snippet": "keepalive_timeout 10s;",
+			},
+			warnings: "WARNHAProxy Ingress doesn't support retries through Ingress annotations, retry the requests on the clients instead\n" +
+				"WARNHAProxy Ingress doesn't support the ingress-nginx nginx.ingress.kubernetes.io/configuration-snippet, nginx.ingress.kubernetes.io/rewrite-target, nginx.ingress.kubernetes.io/use-regex annotations, they are left out of the webapp-books-id Ingress\n",
+		},
+		{
+			name:       "traefik",
+			controller: options.IngressControllerTraefik,
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/server-snippet": "keepalive_timeout 10s;",
+			},
+			warnings: "WARNTraefik doesn't support retries through Ingress annotations, use the traefik generator to generate Middlewares instead\n" +
+				"WARNTraefik doesn't support the ingress-nginx nginx.ingress.kubernetes.io/configuration-snippet, nginx.ingress.kubernetes.io/rewrite-target, nginx.ingress.kubernetes.io/use-regex annotations, they are left out of the webapp-books-id Ingress\n",
+		},
+		{
+			name:       "alb",
+			controller: options.IngressControllerALB,
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/server-snippet": "keepalive_timeout 10s;",
+			},
+			warnings: "WARNThe AWS Load Balancer Controller doesn't support retries through Ingress annotations, retry the requests on the clients instead\n" +
+				"WARNThe AWS Load Balancer Controller doesn't support the ingress-nginx nginx.ingress.kubernetes.io/configuration-snippet, nginx.ingress.kubernetes.io/rewrite-target, nginx.ingress.kubernetes.io/use-regex annotations, they are left out of the webapp-books-id Ingress\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books/{id}:
+    get: {}
+`))
+			r.NoError(err)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Retries: options.RetryOptions{
+					Attempts: 2,
+				},
+				Ingress: options.IngressOptions{
+					Controller: testCase.controller,
+					RequestHeaders: map[string]string{
+						"X-Source": "kusk",
+					},
+					// user-provided annotations are kept whatever the controller
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/server-snippet": "keepalive_timeout 10s;",
+					},
+				},
+			}
+
+			var warnings bytes.Buffer
+			warnOutput = &warnings
+			defer func() { warnOutput = os.Stderr }()
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			r.Equal(testCase.annotations, ingresses[0].Annotations)
+			r.Equal(testCase.warnings, warnings.String())
+		})
+	}
+}
+
+func TestInvalidIngressController(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Ingress: options.IngressOptions{
+			Controller: "envoy",
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+	r.Contains(err.Error(), "ingress.controller must be one of nginx, haproxy, traefik or alb")
+}
+
+func TestALB(t *testing.T) {
+	testCases := []struct {
+		name        string
+		alb         options.ALBOptions
+		controller  string
+		annotations map[string]string
+	}{
+		{
+			name:        "no options",
+			controller:  options.IngressControllerALB,
+			annotations: map[string]string{},
+		},
+		{
+			name: "all options",
+			alb: options.ALBOptions{
+				Scheme:      options.ALBSchemeInternetFacing,
+				TargetType:  options.ALBTargetTypeIP,
+				ListenPorts: []string{"HTTP:80", "HTTPS:443"},
+				GroupName:   "shared.webapps",
+			},
+			controller: options.IngressControllerALB,
+			annotations: map[string]string{
+				albSchemeAnnotationKey:      "internet-facing",
+				albTargetTypeAnnotationKey:  "ip",
+				albListenPortsAnnotationKey: `[{"HTTP":80},{"HTTPS":443}]`,
+				albGroupNameAnnotationKey:   "shared.webapps",
+			},
+		},
+		{
+			name: "ignored by other controllers",
+			alb: options.ALBOptions{
+				Scheme: options.ALBSchemeInternal,
+			},
+			annotations: map[string]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(singlePathSpec))
+			r.NoError(err)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+					Port:      80,
+				},
+				Ingress: options.IngressOptions{
+					Controller: testCase.controller,
+				},
+				ALB: testCase.alb,
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			r.Equal(testCase.annotations, ingresses[0].Annotations)
+		})
+	}
+}
+
+func TestInvalidALBOptions(t *testing.T) {
+	testCases := []struct {
+		name  string
+		alb   options.ALBOptions
+		error string
+	}{
+		{
+			name:  "scheme",
+			alb:   options.ALBOptions{Scheme: "public"},
+			error: "alb.scheme must be one of internet-facing or internal",
+		},
+		{
+			name:  "target type",
+			alb:   options.ALBOptions{TargetType: "pod"},
+			error: "alb.target_type must be one of instance or ip",
+		},
+		{
+			name:  "listen port protocol",
+			alb:   options.ALBOptions{ListenPorts: []string{"TCP:80"}},
+			error: `alb.listen_ports "TCP:80" must be in the form of HTTP:port or HTTPS:port`,
+		},
+		{
+			name:  "listen port number",
+			alb:   options.ALBOptions{ListenPorts: []string{"HTTPS:70000"}},
+			error: `alb.listen_ports "HTTPS:70000" must have a port between 1 and 65535`,
+		},
+		{
+			name:  "group name",
+			alb:   options.ALBOptions{GroupName: "Shared_Webapps"},
+			error: "alb.group_name must consist of lower case alphanumeric characters",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Ingress: options.IngressOptions{
+					Controller: options.IngressControllerALB,
+				},
+				ALB: testCase.alb,
+			}
+
+			var gen Generator
+			_, err := gen.Generate(&opts, &openapi3.T{})
+			r.Error(err)
+			r.Contains(err.Error(), testCase.error)
+		})
+	}
+}
+
+func TestNoPaths(t *testing.T) {
+	testCases := []struct {
+		name       string
+		spec       string
+		allowEmpty bool
+		error      string
+	}{
+		{
+			name: "all paths disabled",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    x-kusk:
+      disabled: true
+    get: {}
+  /authors:
+    x-kusk:
+      disabled: true
+    get: {}
+`,
+			error: "all the spec paths are disabled or filtered out, use --allow-empty to allow it",
+		},
+		{
+			name: "no paths",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths: {}
+`,
+			error: "the spec has no paths to generate Ingress rules for, use --path.default to route all the requests under path.base or --allow-empty to allow it",
+		},
+		{
+			name: "all paths disabled, allowed",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    x-kusk:
+      disabled: true
+    get: {}
+`,
+			allowEmpty: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err)
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err)
+
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+			opts.AllowEmpty = testCase.allowEmpty
+
+			var gen Generator
+			res, err := gen.Generate(opts, apiSpec)
+			if testCase.error != "" {
+				r.EqualError(err, testCase.error)
+				return
+			}
+
+			r.NoError(err)
+			r.Empty(res)
+		})
+	}
+}
+
+func TestPathDefault(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Proxy
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+	opts.Path.Default = true
+	// a split has no paths to split
+	opts.Path.Split = true
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 1)
+
+	r.Equal("webapp-ingress", ingresses[0].Name)
+	r.Len(ingresses[0].Spec.Rules, 1)
+	r.Equal("api.example.com", ingresses[0].Spec.Rules[0].Host)
+	r.Len(ingresses[0].Spec.Rules[0].HTTP.Paths, 1)
+	r.Equal("/v1", ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+	r.Equal(pathTypePrefix, *ingresses[0].Spec.Rules[0].HTTP.Paths[0].PathType)
+
+	paths, err := gen.ListPaths(opts, apiSpec)
+	r.NoError(err)
+	r.Equal([]generators.Path{{Path: "/v1", PathType: "Prefix", Name: "webapp-ingress"}}, paths)
+}
+
+func TestNameTemplate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		nameTemplate string
+		namePrefix   string
+		split        bool
+		res          []string
+		error        bool
+	}{
+		{
+			name: "default",
+			res:  []string{"webapp-ingress"},
+		},
+		{
+			name:  "default split",
+			split: true,
+			res:   []string{"webapp-books", "webapp-books-id"},
+		},
+		{
+			name:         "custom",
+			nameTemplate: "{{.Namespace}}-{{.Service}}-{{.Host}}",
+			res:          []string{"books-webapp-example-org"},
+		},
+		{
+			name:         "custom split",
+			nameTemplate: "{{.Service}}.{{.Path}}.http",
+			split:        true,
+			res:          []string{"webapp-books-http", "webapp-books-id-http"},
+		},
+		{
+			name:       "prefix",
+			namePrefix: "tenant-",
+			res:        []string{"tenant-webapp-ingress"},
+		},
+		{
+			name:       "prefix split",
+			namePrefix: "tenant-",
+			split:      true,
+			res:        []string{"tenant-webapp-books", "tenant-webapp-books-id"},
+		},
+		{
+			name:         "prefix custom",
+			nameTemplate: "{{.Namespace}}-{{.Service}}",
+			namePrefix:   "tenant-",
+			res:          []string{"tenant-books-webapp"},
+		},
+		{
+			name:         "prefix truncated",
+			nameTemplate: "{{.Service}}-with-a-name-long-enough-to-exceed-the-limit-once-prefixed",
+			namePrefix:   "tenant-",
+			res:          []string{"tenant-webapp-with-a-name-long-enough-to-exceed-the-li-9fa4db37"},
+		},
+		{
+			name:         "invalid template",
+			nameTemplate: "{{.Service",
+			error:        true,
+		},
+		{
+			name:         "unknown field",
+			nameTemplate: "{{.Name}}",
+			error:        true,
+		},
+		{
+			name:         "empty name",
+			nameTemplate: "{{.Path}}",
+			error:        true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+			r.NoError(err)
+
+			opts := options.Options{
+				Namespace:  "books",
+				Host:       "example.org",
+				NamePrefix: testCase.namePrefix,
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Split: testCase.split,
+				},
+				Ingress: options.IngressOptions{
+					NameTemplate: testCase.nameTemplate,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+
+			names := make([]string, 0, len(ingresses))
+			for _, ingress := range ingresses {
+				names = append(names, ingress.Name)
+			}
+			r.Equal(testCase.res, names)
+		})
+	}
+}
+
+func TestMergeStatic(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /authors:
+    get: {}
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+  /genres:
+    get: {}
+  /reviews:
+    x-kusk:
+      cors:
+        origins:
+          - https://example.org
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Service = options.ServiceOptions{
+		Namespace: "default",
+		Name:      "webapp",
+		Port:      80,
+	}
+	opts.Path.Split = true
+	opts.Ingress.MergeStatic = true
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+
+	names := make([]string, 0, len(ingresses))
+	for _, ingress := range ingresses {
+		names = append(names, ingress.Name)
+	}
+	r.Equal([]string{"webapp-books-id", "webapp-ingress", "webapp-reviews"}, names)
+
+	merged := ingresses[1]
+	r.NotContains(merged.Annotations, rewriteTargetAnnotationKey)
+
+	paths := make([]string, 0)
+	for _, path := range merged.Spec.Rules[0].HTTP.Paths {
+		paths = append(paths, path.Path)
+		r.Equal(pathTypeExact, *path.PathType)
+		r.Equal("webapp", path.Backend.Service.Name)
+	}
+	r.Equal([]string{"/authors", "/books", "/genres"}, paths)
+
+	// the templated path and the path with different annotations keep their own Ingress
+	r.Len(ingresses[0].Spec.Rules[0].HTTP.Paths, 1)
+	r.Equal("/books/([^/]+)", ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+	r.Len(ingresses[2].Spec.Rules[0].HTTP.Paths, 1)
+	r.Equal("https://example.org", ingresses[2].Annotations["nginx.ingress.kubernetes.io/cors-allow-origin"])
+}
+
+func TestPathVariables(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /users/{user_id}:
+    get: {}
+  /orgs/{orgId2}:
+    get: {}
+  /files/{na` + "`" + `me}:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+	}
+	r.NoError(opts.FillDefaultsAndValidate())
+
+	var gen Generator
+	ingresses, err := gen.splitPath(&opts, apiSpec)
+	r.NoError(err)
+
+	paths := map[string]string{}
+	for _, ingress := range ingresses {
+		paths[ingress.Name] = ingress.Spec.Rules[0].HTTP.Paths[0].Path
+	}
+
+	r.Equal(map[string]string{
+		"webapp-users-user-id": "/users/([^/]+)",
+		"webapp-orgs-orgid2":   "/orgs/([^/]+)",
+		// not a valid parameter name, kept as a literal path segment
+		"webapp-files-na-me": "/files/{na`me}",
+	}, paths)
+}
+
+func TestMultiplePathVariables(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /orgs/{orgId}/users/{userId}:
+    get: {}
+`))
+	r.NoError(err)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Base:  "/api",
+			Split: true,
+		},
+	}
+	r.NoError(opts.FillDefaultsAndValidate())
+
+	var gen Generator
+	ingresses, err := gen.splitPath(&opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 1)
+
+	r.Equal("/api/orgs/([^/]+)/users/([^/]+)", ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+	r.Equal("/api/orgs/$1/users/$2", ingresses[0].Annotations[rewriteTargetAnnotationKey])
+}
+
+func TestPathVariableRegex(t *testing.T) {
+	testCases := []struct {
+		name          string
+		variableRegex string
+		path          string
+		error         string
+	}{
+		{
+			name: "default",
+			path: "/users/([^/]+)",
+		},
+		{
+			name:          "custom",
+			variableRegex: "[0-9a-f]{8}(?:-[0-9a-f]{4}){3}-[0-9a-f]{12}",
+			path:          "/users/([0-9a-f]{8}(?:-[0-9a-f]{4}){3}-[0-9a-f]{12})",
+		},
+		{
+			name:          "invalid",
+			variableRegex: "[0-9",
+			error:         "path.variable_regex is not a valid regular expression",
+		},
+		{
+			name:          "capture group",
+			variableRegex: "([0-9]+)",
+			error:         "path.variable_regex must not contain capture groups",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /users/{id}:
+    get: {}
+`))
+			r.NoError(err)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Split:         true,
+					VariableRegex: testCase.variableRegex,
+				},
+			}
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			if testCase.error != "" {
+				r.Error(err)
+				r.Contains(err.Error(), testCase.error)
+				return
+			}
+
+			r.NoError(err)
+			r.Len(ingresses, 1)
+
+			path := ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path
+			r.Equal(testCase.path, path)
+			r.Equal("/users/$1", ingresses[0].Annotations[rewriteTargetAnnotationKey])
+
+			// a UUID is routed, a nested path isn't
+			re := regexp.MustCompile("^" + path + "$")
+			r.True(re.MatchString("/users/6f1c2a4e-9b7d-4c3e-8a5f-0d2e1b3c4a5f"))
+			r.False(re.MatchString("/users/6f1c2a4e-9b7d-4c3e-8a5f-0d2e1b3c4a5f/orders"))
+		})
+	}
+}
+
+func TestOperationRateLimits(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+x-kusk:
+  namespace: default
+  service:
+    name: webapp
+    namespace: default
+  rate_limits:
+    rps: 100
+paths:
+  /health:
+    get: {}
+  /reports:
+    x-kusk:
+      rate_limits:
+        rps: 20
+    get: {}
+    post:
+      x-kusk:
+        rate_limits:
+          rps: 5
+          burst: 10
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 2)
+
+	// inherits the global rate limit
+	r.Equal("webapp-health", ingresses[0].Name)
+	r.Equal("100", ingresses[0].Annotations["nginx.ingress.kubernetes.io/limit-rps"])
+	r.NotContains(ingresses[0].Annotations, "nginx.ingress.kubernetes.io/limit-burst-multiplier")
+
+	// the lowest rate limit of the path operations applies
+	r.Equal("webapp-reports", ingresses[1].Name)
+	r.Equal("5", ingresses[1].Annotations["nginx.ingress.kubernetes.io/limit-rps"])
+	r.Equal("2", ingresses[1].Annotations["nginx.ingress.kubernetes.io/limit-burst-multiplier"])
+
+	// without the expensive operation the path rate limit applies
+	opts.OperationSubOptions = nil
+	ingresses, err = gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 2)
+	r.Equal("20", ingresses[1].Annotations["nginx.ingress.kubernetes.io/limit-rps"])
+}
+
+func TestRetries(t *testing.T) {
+	testCases := []struct {
+		name    string
+		retries options.RetryOptions
+		res     map[string]string
+		error   bool
+	}{
+		{
+			name: "unset",
+			res:  map[string]string{},
+		},
+		{
+			name:    "attempts",
+			retries: options.RetryOptions{Attempts: 2},
+			res: map[string]string{
+				"nginx.ingress.kubernetes.io/proxy-next-upstream-tries": "3",
+			},
+		},
+		{
+			name:    "attempts and per try timeout",
+			retries: options.RetryOptions{Attempts: 2, PerTryTimeout: 5},
+			res: map[string]string{
+				"nginx.ingress.kubernetes.io/proxy-next-upstream-tries":   "3",
+				"nginx.ingress.kubernetes.io/proxy-next-upstream-timeout": "15",
+			},
+		},
+		{
+			name:    "negative attempts",
+			retries: options.RetryOptions{Attempts: -1},
+			error:   true,
+		},
+		{
+			name:    "per try timeout without attempts",
+			retries: options.RetryOptions{PerTryTimeout: 5},
+			error:   true,
+		},
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+`))
+	require.NoError(t, err)
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Retries: testCase.retries,
+			}
+
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Len(ingresses, 1)
+			r.Equal(testCase.res, ingresses[0].Annotations)
+		})
+	}
+}
+
+func TestIdempotentRetries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		forceAll bool
+		res      map[string]string
+	}{
+		{
+			name: "idempotent only",
+			res: map[string]string{
+				"webapp-books":  "3",
+				"webapp-orders": "",
+			},
+		},
+		{
+			name:     "force all",
+			forceAll: true,
+			res: map[string]string{
+				"webapp-books":  "3",
+				"webapp-orders": "3",
+			},
+		},
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /books:
+    get: {}
+  /orders:
+    post: {}
+`))
+	require.NoError(t, err)
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := options.Options{
+				Service: options.ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+				Path: options.PathOptions{
+					Split: true,
+				},
+				Retries: options.RetryOptions{
+					Attempts: 2,
+					ForceAll: testCase.forceAll,
+				},
+			}
+
+			ingresses, err := gen.generateIngresses(&opts, apiSpec)
+			r.NoError(err)
+
+			res := map[string]string{}
+			for _, ingress := range ingresses {
+				res[ingress.Name] = ingress.Annotations["nginx.ingress.kubernetes.io/proxy-next-upstream-tries"]
+			}
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestInvalidRateLimits(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		RateLimits: options.RateLimitOptions{
+			Burst: 100,
+		},
+	}
+
+	var gen Generator
+	_, err := gen.Generate(&opts, &openapi3.T{})
+	r.Error(err)
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+servers:
+  - url: https://b.example.org
+  - url: https://a.example.org
+paths:
+  /pets:
+    get: {}
+    post: {}
+  /pets/{petId}:
+    get: {}
+    delete: {}
+  /authors:
+    get: {}
+  /books:
+    get: {}
+  /:
+    get: {}
+`))
+	r.NoError(err)
+
+	for _, split := range []bool{false, true} {
+		opts := options.Options{
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+			},
+			Path: options.PathOptions{
+				Split: split,
+			},
+			Ingress: options.IngressOptions{
+				RestrictMethods: true,
+			},
+		}
+
+		var gen Generator
+		first, err := gen.Generate(&opts, apiSpec)
+		r.NoError(err)
+
+		for i := 0; i < 10; i++ {
+			res, err := gen.Generate(&opts, apiSpec)
+			r.NoError(err)
+			r.Equal(first, res)
+		}
+	}
+}
+
+func TestHealthPaths(t *testing.T) {
+	testCases := []struct {
+		name          string
+		internalClass string
+		res           map[string]string
+	}{
+		{
+			name: "left out",
+			res: map[string]string{
+				"webapp-books": "public",
+			},
+		},
+		{
+			name:          "internal class",
+			internalClass: "internal",
+			res: map[string]string{
+				"webapp-books":   "public",
+				"webapp-healthz": "internal",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /healthz:
+    x-kusk:
+      health: true
+      ingress:
+        class: public
+    get: {}
+`))
+			r.NoError(err)
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err)
+
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+			opts.Ingress.Class = "public"
+			opts.Health.InternalClass = testCase.internalClass
+
+			var gen Generator
+			ingresses, err := gen.generateIngresses(opts, apiSpec)
+			r.NoError(err)
+
+			res := map[string]string{}
+			for _, ingress := range ingresses {
+				res[ingress.Name] = *ingress.Spec.IngressClassName
+			}
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestSwaggerSpec(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+swagger: "2.0"
+info:
+  title: Books
+  version: 1.0.0
+host: books.example.com
+basePath: /api
+x-kusk:
+  service:
+    name: webapp
+    namespace: default
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+  /internal:
+    x-kusk:
+      disabled: true
+    get: {}
+`))
+	r.NoError(err)
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err)
+
+	opts.Path.Split = true
+
+	var gen Generator
+	ingresses, err := gen.generateIngresses(opts, apiSpec)
+	r.NoError(err)
+	r.Len(ingresses, 2)
+
+	r.Equal("webapp-books", ingresses[0].Name)
+	r.Equal("books.example.com", ingresses[0].Spec.Rules[0].Host)
+	r.Equal("/api/books", ingresses[0].Spec.Rules[0].HTTP.Paths[0].Path)
+
+	r.Equal("webapp-books-id", ingresses[1].Name)
+	r.Equal("books.example.com", ingresses[1].Spec.Rules[0].Host)
+	r.Equal("/api/books/([^/]+)", ingresses[1].Spec.Rules[0].HTTP.Paths[0].Path)
+}
+
+// capturingLogger records the logged messages along with their verbosity level and key and value pairs
+type capturingLogger struct {
+	level   int
+	entries *[]string
+}
+
+func (l capturingLogger) Enabled() bool {
+	return true
+}
+
+func (l capturingLogger) Info(msg string, keysAndValues ...interface{}) {
+	entry := fmt.Sprintf("V(%d) %s", l.level, msg)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		entry += fmt.Sprintf(" %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+
+	*l.entries = append(*l.entries, entry)
+}
+
+func (l capturingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.Info(msg, append(keysAndValues, "error", err)...)
+}
+
+func (l capturingLogger) V(level int) logr.Logger {
+	l.level += level
+	return l
+}
+
+func (l capturingLogger) WithValues(...interface{}) logr.Logger {
+	return l
+}
+
+func (l capturingLogger) WithName(string) logr.Logger {
+	return l
+}
+
+func TestPathDecisionLogs(t *testing.T) {
+	testCases := []struct {
+		name  string
+		split bool
+		res   []string
+	}{
+		{
+			name:  "split",
+			split: true,
+			res: []string{
+				"V(1) path decision path=/admin decision=excluded reason=path.include or path.exclude",
+				"V(1) path decision path=/internal decision=disabled",
+				"V(1) path decision path=/books decision=included ingress=webapp-books",
+				"V(1) path decision path=/search?type=authors decision=included ingress=webapp-search",
+				"V(1) path decision path=/search?type=books decision=merged ingress=webapp-search",
+			},
+		},
+		{
+			name: "single Ingress",
+			res: []string{
+				"V(1) path decision path=/books decision=included ingress=webapp-ingress",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			paths := `
+  /admin:
+    get: {}
+  /internal:
+    x-kusk:
+      disabled: true
+    get: {}
+  /search?type=authors:
+    get: {}
+  /search?type=books:
+    get: {}
+`
+			if !testCase.split {
+				paths = ""
+			}
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}` + paths))
+			r.NoError(err)
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err)
+
+			var entries []string
+
+			opts.Service = options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+				Port:      80,
+			}
+			opts.Path.Exclude = []string{"/admin"}
+			opts.Logger = capturingLogger{entries: &entries}
+
+			var gen Generator
+			_, err = gen.generateIngresses(opts, apiSpec)
+			r.NoError(err)
+			r.Equal(testCase.res, entries)
 		})
 	}
 }
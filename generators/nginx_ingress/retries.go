@@ -0,0 +1,45 @@
+package nginx_ingress
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+// idempotentMethods are the methods a failed request can be safely retried with,
+// as the request has the same effect whether it reached the upstream or not
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// idempotentRetryOpts returns the retry options of an Ingress routing the given paths.
+// As ingress-nginx can't retry per method, retries are left out if an enabled operation of the paths isn't idempotent,
+// e.g. a POST, unless retries.force_all is set.
+func idempotentRetryOpts(opts *options.Options, spec *openapi3.T, retryOpts options.RetryOptions, paths ...string) options.RetryOptions {
+	if retryOpts.Attempts == 0 || retryOpts.ForceAll {
+		return retryOpts
+	}
+
+	for _, path := range paths {
+		for _, method := range enabledMethods(opts, path, spec.Paths[path]) {
+			if idempotentMethods[method] {
+				continue
+			}
+
+			log.New(warnOutput, "WARN", log.Lmsgprefix).
+				Printf("retries are left out of %s as its %s operation isn't idempotent, use retries.force_all to apply them anyway", path, method)
+
+			return options.RetryOptions{}
+		}
+	}
+
+	return retryOpts
+}
@@ -0,0 +1,56 @@
+package nginx_ingress
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeshop/kusk/generators"
+	"github.com/kubeshop/kusk/options"
+)
+
+const (
+	serviceAPIVersion = "v1"
+
+	serviceKind = "Service"
+)
+
+// newService returns the target Service of the generated Ingresses, exposing the Service port on the same pod port
+func (g *Generator) newService(opts *options.Options) corev1.Service {
+	return corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: serviceAPIVersion,
+			Kind:       serviceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Service.Name,
+			Namespace: opts.Service.Namespace,
+			Labels:    generateLabels(&opts.Ingress),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: opts.Service.Selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       opts.Service.Port,
+					TargetPort: intstr.FromInt(int(opts.Service.Port)),
+				},
+			},
+		},
+	}
+}
+
+func (g *Generator) buildServiceOutput(opts *options.Options) (string, error) {
+	service := g.newService(opts)
+
+	b, err := generators.Marshal(service)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal service resource: %+v: %s", service, err.Error())
+	}
+
+	return "---\n" + string(b), nil
+}
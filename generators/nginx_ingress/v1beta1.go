@@ -0,0 +1,78 @@
+package nginx_ingress
+
+import (
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const ingressV1beta1APIVersion = "networking.k8s.io/v1beta1"
+
+// toV1beta1 converts the Ingress to networking.k8s.io/v1beta1 for clusters older than Kubernetes 1.19,
+// where backends reference the Service by serviceName and servicePort
+func toV1beta1(ingress *v1.Ingress) *v1beta1.Ingress {
+	converted := &v1beta1.Ingress{
+		TypeMeta:   ingress.TypeMeta,
+		ObjectMeta: ingress.ObjectMeta,
+		Spec: v1beta1.IngressSpec{
+			IngressClassName: ingress.Spec.IngressClassName,
+		},
+	}
+	converted.APIVersion = ingressV1beta1APIVersion
+
+	if ingress.Spec.DefaultBackend != nil {
+		backend := toV1beta1Backend(*ingress.Spec.DefaultBackend)
+		converted.Spec.Backend = &backend
+	}
+
+	for _, tls := range ingress.Spec.TLS {
+		converted.Spec.TLS = append(converted.Spec.TLS, v1beta1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		convertedRule := v1beta1.IngressRule{
+			Host: rule.Host,
+		}
+
+		if rule.HTTP != nil {
+			convertedRule.HTTP = &v1beta1.HTTPIngressRuleValue{}
+
+			for _, path := range rule.HTTP.Paths {
+				var pathType *v1beta1.PathType
+				if path.PathType != nil {
+					t := v1beta1.PathType(*path.PathType)
+					pathType = &t
+				}
+
+				convertedRule.HTTP.Paths = append(convertedRule.HTTP.Paths, v1beta1.HTTPIngressPath{
+					Path:     path.Path,
+					PathType: pathType,
+					Backend:  toV1beta1Backend(path.Backend),
+				})
+			}
+		}
+
+		converted.Spec.Rules = append(converted.Spec.Rules, convertedRule)
+	}
+
+	return converted
+}
+
+func toV1beta1Backend(backend v1.IngressBackend) v1beta1.IngressBackend {
+	if backend.Service == nil {
+		return v1beta1.IngressBackend{}
+	}
+
+	port := intstr.FromInt(int(backend.Service.Port.Number))
+	if backend.Service.Port.Name != "" {
+		port = intstr.FromString(backend.Service.Port.Name)
+	}
+
+	return v1beta1.IngressBackend{
+		ServiceName: backend.Service.Name,
+		ServicePort: port,
+	}
+}
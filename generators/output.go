@@ -0,0 +1,217 @@
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DocumentSeparator indicates the start of a YAML document in the generated output
+const DocumentSeparator = "---\n"
+
+var (
+	unsafeFileNameCharsRegex = regexp.MustCompile(`[^a-z0-9._]+`)
+
+	documentSeparatorRegex = regexp.MustCompile(`(?m)^---[ \t]*$`)
+)
+
+// list wraps multiple resources into a single JSON document
+type list struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Items      []json.RawMessage `json:"items"`
+}
+
+// Marshal marshals the resource into a YAML document, leaving out the null metadata.creationTimestamp,
+// also of the pod template of workloads, and the empty status the Kubernetes types marshal to, as they're set by the cluster
+func Marshal(resource interface{}) ([]byte, error) {
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	deleteNullCreationTimestamp(fields)
+
+	if spec, ok := fields["spec"].(map[string]interface{}); ok {
+		if template, ok := spec["template"].(map[string]interface{}); ok {
+			deleteNullCreationTimestamp(template)
+		}
+	}
+
+	if status, ok := fields["status"]; ok && isEmptyField(status) {
+		delete(fields, "status")
+	}
+
+	return yaml.Marshal(fields)
+}
+
+// deleteNullCreationTimestamp deletes the null metadata.creationTimestamp of the decoded JSON object
+func deleteNullCreationTimestamp(fields map[string]interface{}) {
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		if creationTimestamp, ok := metadata["creationTimestamp"]; ok && creationTimestamp == nil {
+			delete(metadata, "creationTimestamp")
+		}
+	}
+}
+
+// isEmptyField returns whether the decoded JSON field is null or an object of empty fields only
+func isEmptyField(field interface{}) bool {
+	switch f := field.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for _, value := range f {
+			if !isEmptyField(value) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// MarshalResources marshals the given resources into YAML documents, each one preceded by a DocumentSeparator
+func MarshalResources(resources []runtime.Object) (string, error) {
+	var builder strings.Builder
+
+	for _, resource := range resources {
+		b, err := Marshal(resource)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal %s resource: %+v: %w", resource.GetObjectKind().GroupVersionKind().Kind, resource, err)
+		}
+
+		builder.WriteString(DocumentSeparator)
+		builder.Write(b)
+	}
+
+	return builder.String(), nil
+}
+
+// Files holds generated resources to be written into separate files, the map key is the file name.
+type Files map[string]string
+
+// Add adds the content of a resource under a file name derived from the given name.
+// An error is returned if another resource has already been added under the same file name.
+func (f Files) Add(name, content string) error {
+	fileName := SanitizeFileName(name) + ".yaml"
+
+	if _, ok := f[fileName]; ok {
+		return fmt.Errorf("resource %s would overwrite another resource in file %s", name, fileName)
+	}
+
+	f[fileName] = content
+
+	return nil
+}
+
+// Write writes the files into the given directory, creating it if needed.
+// Existing files are only overwritten when force is set.
+func (f Files) Write(dir string, force bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for fileName, content := range f {
+		path := filepath.Join(dir, fileName)
+
+		_, err := os.Stat(path)
+		if err == nil && !force {
+			return fmt.Errorf("output file %s already exists, use --force to overwrite it", path)
+		}
+
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to check output file %s: %w", path, err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write output file %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// SanitizeFileName lowercases the name and replaces each run of characters that are not safe in a file name with a dash
+func SanitizeFileName(name string) string {
+	sanitized := unsafeFileNameCharsRegex.ReplaceAllString(strings.ToLower(name), "-")
+	sanitized = strings.Trim(sanitized, "-.")
+
+	if sanitized == "" {
+		return "resource"
+	}
+
+	return sanitized
+}
+
+// TrimLeadingSeparator removes the separator before the first document of the output,
+// keeping the ones between subsequent documents
+func TrimLeadingSeparator(output string) string {
+	return strings.TrimPrefix(output, DocumentSeparator)
+}
+
+// TrimLeadingSeparator removes the separator before the first document of each file
+func (f Files) TrimLeadingSeparator() {
+	for fileName, content := range f {
+		f[fileName] = TrimLeadingSeparator(content)
+	}
+}
+
+// SplitDocuments splits the multi-document YAML output of a generator into its documents, leaving out the empty ones
+func SplitDocuments(output string) []string {
+	var documents []string
+	for _, document := range documentSeparatorRegex.Split(output, -1) {
+		if strings.TrimSpace(document) != "" {
+			documents = append(documents, document)
+		}
+	}
+
+	return documents
+}
+
+// ToJSONList converts the multi-document YAML output of a generator
+// into a JSON List resource wrapping each of the generated resources
+func ToJSONList(output string) (string, error) {
+	l := list{
+		APIVersion: "v1",
+		Kind:       "List",
+		Items:      []json.RawMessage{},
+	}
+
+	for i, document := range SplitDocuments(output) {
+		item, err := yaml.YAMLToJSON([]byte(document))
+		if err != nil {
+			return "", fmt.Errorf("failed to convert resource %d to JSON: %w", i, err)
+		}
+
+		// a document consisting of comments only
+		if string(item) == "null" {
+			continue
+		}
+
+		l.Items = append(l.Items, item)
+	}
+
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resources list: %w", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,171 @@
+package generators
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	testCases := []struct {
+		name string
+		res  string
+	}{
+		{name: "webapp-books-id", res: "webapp-books-id"},
+		{name: "WebApp Books", res: "webapp-books"},
+		{name: "../../etc/passwd", res: "etc-passwd"},
+		{name: "webapp-{id}", res: "webapp-id"},
+		{name: "///", res: "resource"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.res, SanitizeFileName(testCase.name))
+		})
+	}
+}
+
+func TestFiles(t *testing.T) {
+	r := require.New(t)
+
+	files := Files{}
+	r.NoError(files.Add("webapp-books", "---\nkind: Ingress\n"))
+	r.NoError(files.Add("webapp-authors", "---\nkind: Ingress\n"))
+
+	// a name sanitized into an already added file name
+	r.Error(files.Add("WebApp-Books", "---\nkind: Ingress\n"))
+
+	dir := filepath.Join(t.TempDir(), "out")
+	r.NoError(files.Write(dir, false))
+
+	for _, fileName := range []string{"webapp-books.yaml", "webapp-authors.yaml"} {
+		b, err := os.ReadFile(filepath.Join(dir, fileName))
+		r.NoError(err)
+		r.Equal("---\nkind: Ingress\n", string(b))
+	}
+
+	r.Error(files.Write(dir, false))
+	r.NoError(files.Write(dir, true))
+}
+
+func TestMarshal(t *testing.T) {
+	r := require.New(t)
+
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "webapp-ingress",
+		},
+	}
+
+	b, err := Marshal(ingress)
+	r.NoError(err)
+	r.Equal(`apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: webapp-ingress
+spec: {}
+`, string(b))
+	r.NotContains(string(b), "creationTimestamp")
+	r.NotContains(string(b), "status")
+
+	// a non-empty status is kept
+	ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+
+	b, err = Marshal(ingress)
+	r.NoError(err)
+	r.Contains(string(b), `status:
+  loadBalancer:
+    ingress:
+    - ip: 10.0.0.1
+`)
+
+	// the pod template of a workload has no creation timestamp either
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "webapp",
+		},
+	}
+
+	b, err = Marshal(deployment)
+	r.NoError(err)
+	r.NotContains(string(b), "creationTimestamp")
+	r.Contains(string(b), "  template:\n    metadata: {}\n")
+}
+
+func TestTrimLeadingSeparator(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+		res    string
+	}{
+		{
+			name:   "single document",
+			output: "---\nkind: Ingress\n",
+			res:    "kind: Ingress\n",
+		},
+		{
+			name:   "multiple documents",
+			output: "---\nkind: Ingress\n---\nkind: Ingress\n",
+			res:    "kind: Ingress\n---\nkind: Ingress\n",
+		},
+		{
+			name:   "no leading separator",
+			output: "kind: Ingress\n---\nkind: Ingress\n",
+			res:    "kind: Ingress\n---\nkind: Ingress\n",
+		},
+		{
+			name:   "empty",
+			output: "",
+			res:    "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.res, TrimLeadingSeparator(testCase.output))
+		})
+	}
+}
+
+func TestToJSONList(t *testing.T) {
+	r := require.New(t)
+
+	res, err := ToJSONList("---\napiVersion: v1\nkind: Service\nmetadata:\n  name: a\n\n---\n# comment only\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: b\n")
+	r.NoError(err)
+
+	var l struct {
+		APIVersion string                   `json:"apiVersion"`
+		Kind       string                   `json:"kind"`
+		Items      []map[string]interface{} `json:"items"`
+	}
+	r.NoError(json.Unmarshal([]byte(res), &l))
+	r.Equal("v1", l.APIVersion)
+	r.Equal("List", l.Kind)
+	r.Equal([]map[string]interface{}{
+		{"apiVersion": "v1", "kind": "Service", "metadata": map[string]interface{}{"name": "a"}},
+		{"apiVersion": "v1", "kind": "Service", "metadata": map[string]interface{}{"name": "b"}},
+	}, l.Items)
+
+	// no resources result in an empty list rather than null items
+	res, err = ToJSONList("")
+	r.NoError(err)
+	r.JSONEq(`{"apiVersion": "v1", "kind": "List", "items": []}`, res)
+
+	_, err = ToJSONList("---\nkind: [Service\n")
+	r.Error(err)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/pflag"
 
-	"github.com/ghodss/yaml"
 	traefikDynamicConfig "github.com/traefik/traefik/v2/pkg/config/dynamic"
 	traefikCRD "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +17,7 @@ import (
 
 	"github.com/kubeshop/kusk/generators"
 	"github.com/kubeshop/kusk/options"
+	kuskSpec "github.com/kubeshop/kusk/spec"
 )
 
 const (
@@ -29,6 +29,8 @@ const (
 
 var (
 	rePathSymbols = regexp.MustCompile(`[/{}]`)
+
+	openApiPathVariableRegex = regexp.MustCompile(`{([A-Za-z_][A-Za-z0-9_]*)}`)
 )
 
 func init() {
@@ -86,6 +88,18 @@ func (g *Generator) Flags() *pflag.FlagSet {
 		"the Host header value to listen on",
 	)
 
+	fs.StringSlice(
+		"traefik.entrypoints",
+		[]string{},
+		"an entry point for the IngressRoute to listen on, can be repeated (default web)",
+	)
+
+	fs.StringSlice(
+		"traefik.middlewares",
+		[]string{},
+		"an existing Middleware to apply to the routes in the form of name or namespace/name, can be repeated",
+	)
+
 	return fs
 }
 
@@ -103,8 +117,9 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 	}
 	host := opts.Host
 	base := opts.Path.Base
-	// K8s serviceName for created resources are based on service serviceName
+	// K8s names for created resources are based on the prefixed service name
 	serviceName := opts.Service.Name
+	resourceName := opts.NamePrefix + serviceName
 	namespace := opts.Namespace
 
 	// these are all middlewares to create manifests for
@@ -115,34 +130,38 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 	rootMiddlewares := map[string]traefikCRD.Middleware{}
 
 	if opts.Path.TrimPrefix != "" {
-		stripPrefixMiddleware := generateStripPrefixMiddleware(generateResourceName([]string{serviceName, "strip-prefix"}), namespace, opts.Path.TrimPrefix)
+		stripPrefixMiddleware := generateStripPrefixMiddleware(generateResourceName([]string{resourceName, "strip-prefix"}), namespace, opts.Path.TrimPrefix)
 		rootMiddlewares["stripprefix"] = stripPrefixMiddleware
 		allMiddlewares = append(allMiddlewares, stripPrefixMiddleware)
 	}
 
 	// Top level CORS middleware, could be overriden per path/method
 	if !reflect.DeepEqual(options.CORSOptions{}, opts.CORS) {
-		corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, "cors"}), namespace, opts.CORS)
+		corsMiddleware := generateCORSMiddleware(generateResourceName([]string{resourceName, "cors"}), namespace, opts.CORS)
 		rootMiddlewares["cors"] = corsMiddleware
 		allMiddlewares = append(allMiddlewares, corsMiddleware)
 	}
 
 	// Top level RateLimit middleware
 	if !reflect.DeepEqual(options.RateLimitOptions{}, opts.RateLimits) {
-		rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, "ratelimit"}), namespace, opts.RateLimits)
+		rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{resourceName, "ratelimit"}), namespace, opts.RateLimits)
 		rootMiddlewares["ratelimit"] = rateLimitMiddleware
 		allMiddlewares = append(allMiddlewares, rateLimitMiddleware)
 	}
 	// Default top level service servers transport (defines communication with service backend, e.g. timeouts, tls)
-	serviceServersTransport := generateServerTransport(serviceName, namespace, opts.Timeouts)
+	serviceServersTransport := generateServerTransport(resourceName, namespace, opts.Timeouts)
 	allServersTransports := []traefikCRD.ServersTransport{serviceServersTransport}
 
+	// User provided middlewares, applied to all routes
+	customMiddlewaresRefs := generateCustomMiddlewaresRefs(opts.Traefik.Middlewares)
+
 	// Routes to include into ingress
 	routes := []traefikCRD.Route{}
 
 	// Main routine
 	// Iterate on all paths and build routes rules with related middlewares and any overrides
-	for path, pathItem := range spec.Paths {
+	for _, path := range kuskSpec.SortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
 		// x-kusk options per path
 		// ServersTransport for this path
 		pathServiceServersTransport := serviceServersTransport
@@ -156,19 +175,19 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 			}
 			// if non-zero path-level CORS options are different, override with them
 			if !reflect.DeepEqual(options.CORSOptions{}, pathSubOpts.CORS) {
-				corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, path, "cors"}), namespace, pathSubOpts.CORS)
+				corsMiddleware := generateCORSMiddleware(generateResourceName([]string{resourceName, path, "cors"}), namespace, pathSubOpts.CORS)
 				pathMiddlewares["cors"] = corsMiddleware
 				allMiddlewares = append(allMiddlewares, corsMiddleware)
 			}
 
 			if !reflect.DeepEqual(options.RateLimitOptions{}, pathSubOpts.RateLimits) {
-				rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, path, "ratelimit"}), namespace, pathSubOpts.RateLimits)
+				rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{resourceName, path, "ratelimit"}), namespace, pathSubOpts.RateLimits)
 				pathMiddlewares["ratelimit"] = rateLimitMiddleware
 				allMiddlewares = append(allMiddlewares, rateLimitMiddleware)
 			}
 
 			if !reflect.DeepEqual(options.TimeoutOptions{}, pathSubOpts.Timeouts) {
-				pathServiceServersTransport = generateServerTransport(generateResourceName([]string{serviceName, path}), opts.Namespace, pathSubOpts.Timeouts)
+				pathServiceServersTransport = generateServerTransport(generateResourceName([]string{resourceName, path}), opts.Namespace, pathSubOpts.Timeouts)
 				allServersTransports = append(allServersTransports, pathServiceServersTransport)
 			}
 		}
@@ -195,19 +214,19 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 				}
 				// if non-zero operation level CORS options are different, override with them
 				if !reflect.DeepEqual(options.CORSOptions{}, opSubOpts.CORS) {
-					corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, path, method, "cors"}), namespace, opSubOpts.CORS)
+					corsMiddleware := generateCORSMiddleware(generateResourceName([]string{resourceName, path, method, "cors"}), namespace, opSubOpts.CORS)
 					opMiddlewares["cors"] = corsMiddleware
 					allMiddlewares = append(allMiddlewares, corsMiddleware)
 				}
 
 				if !reflect.DeepEqual(options.RateLimitOptions{}, opSubOpts.RateLimits) {
-					rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, path, "ratelimit"}), namespace, opSubOpts.RateLimits)
+					rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{resourceName, path, "ratelimit"}), namespace, opSubOpts.RateLimits)
 					opMiddlewares["ratelimit"] = rateLimitMiddleware
 					allMiddlewares = append(allMiddlewares, rateLimitMiddleware)
 				}
 
 				if !reflect.DeepEqual(options.TimeoutOptions{}, opSubOpts.Timeouts) {
-					opServiceServersTransport = generateServerTransport(generateResourceName([]string{serviceName, path, method}), namespace, opSubOpts.Timeouts)
+					opServiceServersTransport = generateServerTransport(generateResourceName([]string{resourceName, path, method}), namespace, opSubOpts.Timeouts)
 					allServersTransports = append(allServersTransports, opServiceServersTransport)
 				}
 			}
@@ -225,7 +244,7 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 				Match:       matchRule,
 				Services:    []traefikCRD.Service{service},
 				Kind:        "Rule",
-				Middlewares: generateMiddlewaresRefs(middlewareMapToList(opMiddlewares)),
+				Middlewares: append(generateMiddlewaresRefs(middlewareMapToList(opMiddlewares)), customMiddlewaresRefs...),
 			}
 			routes = append(routes, route)
 		}
@@ -236,8 +255,13 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 	}
 
 	// Finally generate Ingress spec and object itself
+	entryPoints := opts.Traefik.EntryPoints
+	if len(entryPoints) == 0 {
+		entryPoints = []string{HTTPEntryPoint}
+	}
+
 	ingressRouteSpec := traefikCRD.IngressRouteSpec{
-		EntryPoints: []string{HTTPEntryPoint},
+		EntryPoints: entryPoints,
 		Routes:      routes,
 	}
 
@@ -248,7 +272,7 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 	ingressRoute := traefikCRD.IngressRoute{
 		Spec:       ingressRouteSpec,
 		TypeMeta:   metav1.TypeMeta{Kind: "IngressRoute", APIVersion: APIVersion},
-		ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: namespace},
 	}
 	return buildOutput(ingressRoute, allMiddlewares, allServersTransports)
 }
@@ -317,6 +341,19 @@ func generateMiddlewaresRefs(middlewares []traefikCRD.Middleware) []traefikCRD.M
 	return middlewaresRefs
 }
 
+// generateCustomMiddlewaresRefs references existing middlewares given in the form of name or namespace/name
+func generateCustomMiddlewaresRefs(middlewares []string) []traefikCRD.MiddlewareRef {
+	middlewaresRefs := []traefikCRD.MiddlewareRef{}
+	for _, m := range middlewares {
+		ref := traefikCRD.MiddlewareRef{Name: m}
+		if i := strings.Index(m, "/"); i >= 0 {
+			ref = traefikCRD.MiddlewareRef{Name: m[i+1:], Namespace: m[:i]}
+		}
+		middlewaresRefs = append(middlewaresRefs, ref)
+	}
+	return middlewaresRefs
+}
+
 func generateMatchRule(host string, base string, path string, method string) string {
 	const httpPathSeparator string = "/"
 	// Avoids path joins (removes // in e.g. /path//subpath, or //subpath)
@@ -327,7 +364,12 @@ func generateMatchRule(host string, base string, path string, method string) str
 	if host != "" {
 		rules = append(rules, fmt.Sprintf("Host(\"%s\")", host))
 	}
-	rules = append(rules, fmt.Sprintf("PathPrefix(\"%s\")", fullPath))
+	if openApiPathVariableRegex.MatchString(fullPath) {
+		// translate path variables to Traefik regex placeholders, e.g. /pets/{petId} -> /pets/{petId:[^/]+}
+		rules = append(rules, fmt.Sprintf("Path(\"%s\")", openApiPathVariableRegex.ReplaceAllString(fullPath, "{${1}:[^/]+}")))
+	} else {
+		rules = append(rules, fmt.Sprintf("PathPrefix(\"%s\")", fullPath))
+	}
 	rules = append(rules, fmt.Sprintf("Method(\"%s\")", method))
 	// returns e.g. Host(`example.org`) && PathPrefix(`/petstore/api/v3/pet`) && Method(`POST`)
 	return strings.Join(rules, " && ")
@@ -353,7 +395,7 @@ func buildOutput(ingressRoute traefikCRD.IngressRoute, middlewares []traefikCRD.
 	})
 	for _, middleware := range middlewares {
 		builder.WriteString("---\n") // indicate start of YAML resource
-		b, err := yaml.Marshal(middleware)
+		b, err := generators.Marshal(middleware)
 		if err != nil {
 			return "", fmt.Errorf("unable to marshal Middleware resource: %+v: %s", middleware, err.Error())
 		}
@@ -361,7 +403,7 @@ func buildOutput(ingressRoute traefikCRD.IngressRoute, middlewares []traefikCRD.
 	}
 	for _, serversTransport := range serversTransports {
 		builder.WriteString("---\n") // indicate start of YAML resource
-		b, err := yaml.Marshal(serversTransport)
+		b, err := generators.Marshal(serversTransport)
 		if err != nil {
 			return "", fmt.Errorf("unable to marshal ServersTransport resource: %+v: %s", serversTransport, err.Error())
 		}
@@ -369,7 +411,7 @@ func buildOutput(ingressRoute traefikCRD.IngressRoute, middlewares []traefikCRD.
 	}
 	// IngressRoute
 	builder.WriteString("---\n") // indicate start of YAML resource
-	b, err := yaml.Marshal(ingressRoute)
+	b, err := generators.Marshal(ingressRoute)
 	if err != nil {
 		return "", fmt.Errorf("unable to marshal IngressRoute resource: %+v: %s", ingressRoute, err.Error())
 	}
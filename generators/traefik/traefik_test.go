@@ -45,7 +45,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -57,7 +56,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -101,7 +99,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -113,7 +110,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -158,7 +154,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
-  creationTimestamp: null
   name: petstore-strip-prefix
   namespace: nondefault
 spec:
@@ -169,7 +164,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -181,7 +175,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -227,7 +220,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -239,7 +231,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -349,7 +340,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
-  creationTimestamp: null
   name: petstore-cors
   namespace: nondefault
 spec:
@@ -369,7 +359,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
-  creationTimestamp: null
   name: petstore-pet-cors
   namespace: nondefault
 spec:
@@ -388,7 +377,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
-  creationTimestamp: null
   name: petstore-pet-post-cors
   namespace: nondefault
 spec:
@@ -407,7 +395,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
-  creationTimestamp: null
   name: petstore-petfindbystatus-get-cors
   namespace: nondefault
 spec:
@@ -423,7 +410,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
-  creationTimestamp: null
   name: petstore-petfindbystatus-ratelimit
   namespace: nondefault
 spec:
@@ -434,7 +420,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -446,7 +431,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore-pet
   namespace: nondefault
 spec:
@@ -458,7 +442,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore-pet-post
   namespace: nondefault
 spec:
@@ -470,7 +453,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -549,7 +531,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -561,7 +542,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -607,7 +587,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -619,7 +598,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -668,7 +646,6 @@ paths:
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -680,7 +657,6 @@ spec:
 apiVersion: traefik.containo.us/v1alpha1
 kind: IngressRoute
 metadata:
-  creationTimestamp: null
   name: petstore
   namespace: nondefault
 spec:
@@ -701,6 +677,69 @@ spec:
       namespace: nondefault
       port: 7777
       serversTransport: petstore
+`,
+		},
+		{
+			name: "custom entry points and middlewares, path variables and disabled path",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+x-kusk:
+  namespace: nondefault
+  host: example.org
+  service:
+    name: petstore
+    namespace: nondefault
+    port: 7000
+  traefik:
+    entrypoints:
+      - websecure
+    middlewares:
+      - auth
+      - traefik/compress
+paths:
+  "/pet/{petId}":
+    get: {}
+  "/internal":
+    x-kusk:
+      disabled: true
+    get: {}
+`,
+			res: `
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  name: petstore
+  namespace: nondefault
+spec:
+  forwardingTimeouts:
+    dialTimeout: 0
+    idleConnTimeout: 0
+    responseHeaderTimeout: 0
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: IngressRoute
+metadata:
+  name: petstore
+  namespace: nondefault
+spec:
+  entryPoints:
+  - websecure
+  routes:
+  - kind: Rule
+    match: Host("example.org") && Path("/pet/{petId:[^/]+}") && Method("GET")
+    middlewares:
+    - name: auth
+    - name: compress
+      namespace: traefik
+    services:
+    - name: petstore
+      namespace: nondefault
+      port: 7000
+      serversTransport: petstore
 `,
 		},
 	}
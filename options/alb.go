@@ -0,0 +1,77 @@
+package options
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// albGroupNameRegex matches an IngressGroup name, see
+// https://kubernetes-sigs.github.io/aws-load-balancer-controller/latest/guide/ingress/annotations/#ingressgroup
+var albGroupNameRegex = regexp.MustCompile(`^[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`)
+
+const (
+	ALBSchemeInternetFacing = "internet-facing"
+	ALBSchemeInternal       = "internal"
+
+	ALBTargetTypeInstance = "instance"
+	ALBTargetTypeIP       = "ip"
+)
+
+// ALBOptions are the options of the AWS Load Balancer Controller, applied with ingress.controller alb
+type ALBOptions struct {
+	// Scheme is whether the load balancer is internet-facing or internal. The controller defaults to internal.
+	Scheme string `yaml:"scheme,omitempty" json:"scheme,omitempty"`
+
+	// TargetType is how the load balancer routes to the pods, through the node ports of the Service with instance,
+	// or to the pod IPs directly with ip, which ClusterIP Services require. The controller defaults to instance.
+	TargetType string `yaml:"target_type,omitempty" json:"target_type,omitempty"`
+
+	// ListenPorts are the ports the load balancer listens on, as protocol:port, e.g. HTTPS:443.
+	// The controller defaults to HTTP:80, or HTTPS:443 with a certificate.
+	ListenPorts []string `yaml:"listen_ports,omitempty" json:"listen_ports,omitempty"`
+
+	// GroupName is the IngressGroup the Ingresses join, sharing a single load balancer with the other Ingresses of the group.
+	GroupName string `yaml:"group_name,omitempty" json:"group_name,omitempty"`
+}
+
+func (o *ALBOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Scheme,
+			v.In(ALBSchemeInternetFacing, ALBSchemeInternal).Error("alb.scheme must be one of internet-facing or internal"),
+		),
+		v.Field(&o.TargetType,
+			v.In(ALBTargetTypeInstance, ALBTargetTypeIP).Error("alb.target_type must be one of instance or ip"),
+		),
+		v.Field(&o.ListenPorts, v.Each(v.By(validateALBListenPort))),
+		v.Field(&o.GroupName,
+			v.Length(0, 63).Error("alb.group_name must be at most 63 characters long"),
+			v.Match(albGroupNameRegex).Error("alb.group_name must consist of lower case alphanumeric characters, '-' or '.', and start and end with an alphanumeric character"),
+		),
+	)
+}
+
+// ParseALBListenPort splits a listen port in the form of protocol:port, e.g. HTTPS:443
+func ParseALBListenPort(listenPort string) (protocol string, port int, err error) {
+	parts := strings.SplitN(listenPort, ":", 2)
+	if len(parts) != 2 || (parts[0] != "HTTP" && parts[0] != "HTTPS") {
+		return "", 0, fmt.Errorf("alb.listen_ports %q must be in the form of HTTP:port or HTTPS:port", listenPort)
+	}
+
+	port, err = strconv.Atoi(parts[1])
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("alb.listen_ports %q must have a port between 1 and 65535", listenPort)
+	}
+
+	return parts[0], port, nil
+}
+
+func validateALBListenPort(value interface{}) error {
+	s, _ := value.(string)
+	_, _, err := ParseALBListenPort(s)
+
+	return err
+}
@@ -0,0 +1,60 @@
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var apisixPluginNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+type APISIXOptions struct {
+	// Plugins is a list of APISIX plugins to enable on all the generated routes, each in the form of name
+	// or name=config, where config is a JSON object, e.g. cors={"allow_origins":"http://foo.example"}.
+	Plugins []string `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+}
+
+// APISIXPlugin is an APISIX plugin parsed from APISIXOptions.Plugins
+type APISIXPlugin struct {
+	Name   string
+	Config map[string]interface{}
+}
+
+// ParsedPlugins returns the plugins parsed from their name=config form
+func (o *APISIXOptions) ParsedPlugins() ([]APISIXPlugin, error) {
+	plugins := make([]APISIXPlugin, 0, len(o.Plugins))
+
+	for _, plugin := range o.Plugins {
+		parts := strings.SplitN(plugin, "=", 2)
+
+		parsed := APISIXPlugin{
+			Name: parts[0],
+		}
+
+		if !apisixPluginNameRegex.MatchString(parsed.Name) {
+			return nil, fmt.Errorf("apisix.plugins: %q is not a valid plugin name", parsed.Name)
+		}
+
+		if len(parts) == 2 {
+			if err := json.Unmarshal([]byte(parts[1]), &parsed.Config); err != nil || parsed.Config == nil {
+				return nil, fmt.Errorf("apisix.plugins: the config of %s must be a JSON object", parsed.Name)
+			}
+		}
+
+		plugins = append(plugins, parsed)
+	}
+
+	return plugins, nil
+}
+
+func (o *APISIXOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Plugins, v.By(func(interface{}) error {
+			_, err := o.ParsedPlugins()
+			return err
+		})),
+	)
+}
@@ -1,9 +1,13 @@
 package options
 
 import (
+	"regexp"
+
 	"github.com/go-ozzo/ozzo-validation/v4"
 )
 
+var clusterDomainRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
 type ClusterOptions struct {
 	// ClusterDomain is the base DNS domain for the cluster. Default value is "cluster.local".
 	ClusterDomain string `yaml:"cluster_domain,omitempty" json:"cluster_domain,omitempty"`
@@ -11,6 +15,9 @@ type ClusterOptions struct {
 
 func (o *ClusterOptions) Validate() error {
 	return validation.ValidateStruct(o,
-		validation.Field(&o.ClusterDomain, validation.Required.Error("cluster_domain is required")),
+		validation.Field(&o.ClusterDomain,
+			validation.Required.Error("cluster_domain is required"),
+			validation.Match(clusterDomainRegex).Error("cluster_domain must be a valid DNS name"),
+		),
 	)
 }
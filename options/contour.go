@@ -0,0 +1,24 @@
+package options
+
+import (
+	"regexp"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var contourSecretRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`)
+
+type ContourOptions struct {
+	// FQDN overrides the Host as the fully qualified domain name of the HTTPProxy virtual host.
+	FQDN string `yaml:"fqdn,omitempty" json:"fqdn,omitempty"`
+
+	// TLSSecret is the name of the Secret containing the TLS certificate for the virtual host.
+	// A Secret in another namespace can be referenced as namespace/name, a TLSCertificateDelegation is generated then.
+	TLSSecret string `yaml:"tls_secret,omitempty" json:"tls_secret,omitempty"`
+}
+
+func (o *ContourOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.TLSSecret, v.Match(contourSecretRegex).Error("contour.tls_secret must be in the form of name or namespace/name")),
+	)
+}
@@ -1,6 +1,10 @@
 package options
 
-import "reflect"
+import (
+	"reflect"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
 
 type CORSOptions struct {
 	Origins       []string `yaml:"origins,omitempty" json:"origins,omitempty"`
@@ -12,6 +16,57 @@ type CORSOptions struct {
 	// Check if not nil to ensure it's been set by user
 	Credentials *bool `yaml:"credentials,omitempty" json:"credentials,omitempty"`
 	MaxAge      int   `yaml:"max_age,omitempty" json:"max_age,omitempty"`
+
+	// GRPCWeb fills the methods, headers, exposed headers and max age left unset
+	// with the ones gRPC-Web clients need. The origins still have to be set.
+	GRPCWeb bool `yaml:"grpc_web,omitempty" json:"grpc_web,omitempty"`
+}
+
+var (
+	grpcWebCORSMethods = []string{"POST", "OPTIONS"}
+
+	grpcWebCORSHeaders = []string{
+		"keep-alive",
+		"user-agent",
+		"cache-control",
+		"content-type",
+		"content-transfer-encoding",
+		"x-accept-content-transfer-encoding",
+		"x-accept-response-streaming",
+		"x-user-agent",
+		"x-grpc-web",
+		"grpc-timeout",
+	}
+
+	grpcWebCORSExposeHeaders = []string{"grpc-status", "grpc-message", "grpc-status-details-bin"}
+
+	// grpcWebCORSMaxAge is the number of seconds browsers can cache the preflight requests of gRPC-Web clients, i.e. 20 days
+	grpcWebCORSMaxAge = 1728000
+)
+
+// withGRPCWebDefaults returns a copy of the options with the unset ones filled with the gRPC-Web ones, if enabled
+func (o CORSOptions) withGRPCWebDefaults() CORSOptions {
+	if !o.GRPCWeb {
+		return o
+	}
+
+	if len(o.Methods) == 0 {
+		o.Methods = grpcWebCORSMethods
+	}
+
+	if len(o.Headers) == 0 {
+		o.Headers = grpcWebCORSHeaders
+	}
+
+	if len(o.ExposeHeaders) == 0 {
+		o.ExposeHeaders = grpcWebCORSExposeHeaders
+	}
+
+	if o.MaxAge == 0 {
+		o.MaxAge = grpcWebCORSMaxAge
+	}
+
+	return o
 }
 
 func (o *Options) GetCORSOpts(path, method string) CORSOptions {
@@ -27,16 +82,21 @@ func (o *Options) GetCORSOpts(path, method string) CORSOptions {
 	}
 
 	// if non-zero operation-level CORS options are different, override them
-	if opSubOpts, ok := o.OperationSubOptions[path]; ok {
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
 		if !reflect.DeepEqual(CORSOptions{}, opSubOpts.CORS) &&
 			!reflect.DeepEqual(corsOpts, opSubOpts.CORS) {
 			corsOpts = opSubOpts.CORS
 		}
 	}
 
-	return corsOpts
+	return corsOpts.withGRPCWebDefaults()
 }
 
 func (o *CORSOptions) Validate() error {
-	return nil
+	credentials := o.Credentials != nil && *o.Credentials
+
+	return v.ValidateStruct(o,
+		v.Field(&o.Origins, v.When(credentials, v.Each(v.NotIn("*").Error("cors.origins can't contain * when cors.credentials is enabled")))),
+		v.Field(&o.MaxAge, v.Min(0)),
+	)
 }
@@ -0,0 +1,30 @@
+package options
+
+import (
+	"regexp"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var (
+	gatewayParentRefRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`)
+
+	// gatewayHostnameRegex matches a DNS name, optionally prefixed by *. for a wildcard. Unlike Ingress hosts, * alone isn't allowed.
+	gatewayHostnameRegex = regexp.MustCompile(`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+type GatewayOptions struct {
+	// ParentRef is the name of the Gateway to attach the generated HTTPRoute to.
+	// A Gateway in another namespace can be referenced as namespace/name.
+	ParentRef string `yaml:"parent_ref,omitempty" json:"parent_ref,omitempty"`
+
+	// Hostnames are the hostnames matched by the generated HTTPRoute. Defaults to the host, if set.
+	Hostnames []string `yaml:"hostnames,omitempty" json:"hostnames,omitempty"`
+}
+
+func (o *GatewayOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.ParentRef, v.Match(gatewayParentRefRegex).Error("gateway.parent_ref must be in the form of name or namespace/name")),
+		v.Field(&o.Hostnames, v.Each(v.Match(gatewayHostnameRegex).Error("gateway.hostnames must be valid DNS names, optionally prefixed by *. for a wildcard"))),
+	)
+}
@@ -0,0 +1,25 @@
+package options
+
+import (
+	"regexp"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var glooUpstreamRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`)
+
+type GlooOptions struct {
+	// Upstream overrides the name of the Upstream to route to, which defaults to the one
+	// created by Gloo discovery for the Service. An Upstream in another namespace can be referenced as namespace/name.
+	Upstream string `yaml:"upstream,omitempty" json:"upstream,omitempty"`
+
+	// Namespace overrides the namespace of the generated VirtualService.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+}
+
+func (o *GlooOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Upstream, v.Match(glooUpstreamRegex).Error("gloo.upstream must be in the form of name or namespace/name")),
+		v.Field(&o.Namespace, v.Match(namespaceRegex).Error("gloo.namespace must be a valid RFC 1123 label")),
+	)
+}
@@ -0,0 +1,33 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type HealthOptions struct {
+	// InternalClass is the Ingress class to route the health paths to, e.g. the one of an internal load balancer,
+	// instead of leaving them out of the public Ingresses. Health paths are tagged with health: true at the path level.
+	InternalClass string `yaml:"internal_class,omitempty" json:"internal_class,omitempty"`
+}
+
+func (o *HealthOptions) Validate() error {
+	if o.InternalClass == "" {
+		return nil
+	}
+
+	if errs := validation.IsDNS1123Subdomain(o.InternalClass); len(errs) > 0 {
+		return fmt.Errorf("health.internal_class %q is invalid: %s", o.InternalClass, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// IsPathHealth returns whether the path is tagged as a health path, e.g. for readiness and liveness probes
+func (o *Options) IsPathHealth(path string) bool {
+	pathSubOptions, ok := o.PathSubOptions[path]
+
+	return ok && pathSubOptions.Health != nil && *pathSubOptions.Health
+}
@@ -0,0 +1,419 @@
+package options
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// proxyBodySizeRegex matches an NGINX size, i.e. a number optionally suffixed by k, m or g
+var proxyBodySizeRegex = regexp.MustCompile(`^[0-9]+[kKmMgG]?$`)
+
+// headerNameRegex matches an HTTP header name, i.e. a token as defined by RFC 7230
+var headerNameRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+const (
+	IngressControllerNGINX   = "nginx"
+	IngressControllerHAProxy = "haproxy"
+	IngressControllerTraefik = "traefik"
+	IngressControllerALB     = "alb"
+)
+
+type IngressOptions struct {
+	// Annotations are additional annotations to set on the generated Ingress resources.
+	// They take precedence over the annotations generated by Kusk.
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+
+	// APIVersion is the API version of the generated Ingress resources, networking.k8s.io/v1 by default.
+	// networking.k8s.io/v1beta1 is supported for clusters older than Kubernetes 1.19.
+	APIVersion string `yaml:"api_version,omitempty" json:"api_version,omitempty"`
+
+	// Labels are additional labels to set on all the generated resources.
+	// They take precedence over the labels generated by Kusk, i.e. app.kubernetes.io/managed-by.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+
+	// Controller is the ingress controller the annotations of the CORS, rate limit and timeout options are generated for,
+	// one of nginx, haproxy, traefik or alb, the latter also applying the alb options. Defaults to nginx.
+	Controller string `yaml:"controller,omitempty" json:"controller,omitempty"`
+
+	// Class is the IngressClass of the generated Ingress resources, the ingress.controller name by default.
+	// Can be overridden at the path level, e.g. to expose some paths on an internal ingress controller.
+	Class string `yaml:"class,omitempty" json:"class,omitempty"`
+
+	// PathType forces a path type for all generated Ingress paths,
+	// one of Exact, Prefix or ImplementationSpecific. By default, it is chosen based on the path shape.
+	PathType string `yaml:"path_type,omitempty" json:"path_type,omitempty"`
+
+	// RestrictMethods limits each path to the HTTP methods of the operations defined for it.
+	// Only applies when a separate Ingress is generated for each path.
+	RestrictMethods bool `yaml:"restrict_methods,omitempty" json:"restrict_methods,omitempty"`
+
+	// TLS is a set of options to configure TLS termination for the generated Ingress resources.
+	TLS IngressTLSOptions `yaml:"tls,omitempty" json:"tls,omitempty"`
+
+	// SSLRedirect redirects HTTP requests to HTTPS. Defaults to true if a TLS secret is set, false otherwise.
+	// Can be overridden at the path level, e.g. to serve an ACME challenge path over HTTP.
+	SSLRedirect *bool `yaml:"ssl_redirect,omitempty" json:"ssl_redirect,omitempty"`
+
+	// ForceSSLRedirect redirects HTTP requests to HTTPS even if TLS is not configured on the Ingress,
+	// e.g. when it is terminated by a load balancer in front of the ingress controller.
+	ForceSSLRedirect bool `yaml:"force_ssl_redirect,omitempty" json:"force_ssl_redirect,omitempty"`
+
+	// NameTemplate is a Go text/template used to name the generated Ingress resources,
+	// with access to .Service, .Path, .Namespace and .Host. The result is sanitized into a valid RFC 1123 label.
+	// Defaults to "{{.Service}}-ingress", or "{{.Service}}-{{.Path}}" when a separate Ingress is generated for each path.
+	NameTemplate string `yaml:"name_template,omitempty" json:"name_template,omitempty"`
+
+	// DefaultBackend sets the target Service as the default backend of the generated Ingress resources,
+	// serving the requests that match none of the rules.
+	DefaultBackend bool `yaml:"default_backend,omitempty" json:"default_backend,omitempty"`
+
+	// ProxyBodySize is the maximum allowed size of the request body, e.g. 8m, or 0 to disable the limit.
+	// Can be overridden at the path level.
+	ProxyBodySize string `yaml:"proxy_body_size,omitempty" json:"proxy_body_size,omitempty"`
+
+	// Websocket raises the proxy read and send timeouts so that long-lived websocket connections aren't closed
+	// when idle. Can be overridden at the path level, e.g. to only apply to a websocket endpoint.
+	Websocket *bool `yaml:"websocket,omitempty" json:"websocket,omitempty"`
+
+	// ProxyBuffering turns the buffering of the upstream responses on or off. Can be overridden at the path level,
+	// e.g. to turn it off for a server-sent events endpoint whose events would otherwise be held back.
+	ProxyBuffering string `yaml:"proxy_buffering,omitempty" json:"proxy_buffering,omitempty"`
+
+	// WhitelistSourceRange is a list of CIDRs, e.g. 10.0.0.0/24, the client IP has to be in to be allowed.
+	// All clients are allowed if not set. Can be overridden at the path level, e.g. to only allow office IPs on admin paths.
+	WhitelistSourceRange []string `yaml:"whitelist_source_range,omitempty" json:"whitelist_source_range,omitempty"`
+
+	// RequestHeaders are headers, by name, set on the requests proxied to the upstream Service.
+	// Can be set at the path level, the path-level headers being added to the global ones.
+	RequestHeaders map[string]string `yaml:"request_headers,omitempty" json:"request_headers,omitempty"`
+
+	// ResponseHeaders are headers, by name, set on the responses returned to the clients.
+	// Can be set at the path level, the path-level headers being added to the global ones.
+	ResponseHeaders map[string]string `yaml:"response_headers,omitempty" json:"response_headers,omitempty"`
+
+	// AppendSlash permanently redirects the requests to a path without a trailing slash to the same path with one,
+	// e.g. /books to /books/, for upstreams only serving the latter. Can't be set along with StripSlash.
+	AppendSlash bool `yaml:"append_slash,omitempty" json:"append_slash,omitempty"`
+
+	// StripSlash permanently redirects the requests to a path with a trailing slash to the same path without it,
+	// e.g. /books/ to /books, for upstreams only serving the latter. Can't be set along with AppendSlash.
+	StripSlash bool `yaml:"strip_slash,omitempty" json:"strip_slash,omitempty"`
+
+	// MergeStatic generates a single Ingress for the static paths which are not rewritten, instead of one per path.
+	// Paths with variables and paths needing different annotations still get their own Ingress.
+	// Only applies when a separate Ingress is generated for each path.
+	MergeStatic bool `yaml:"merge_static,omitempty" json:"merge_static,omitempty"`
+
+	// DedupeNames disambiguates the names of the Ingress resources generated for different paths
+	// which sanitize to the same name, e.g. /a/b and /a-b, by suffixing them with a counter.
+	// Such collisions are an error otherwise. Only applies when a separate Ingress is generated for each path.
+	DedupeNames bool `yaml:"dedupe_names,omitempty" json:"dedupe_names,omitempty"`
+
+	// Auth is a set of options to protect the generated Ingress resources with authentication.
+	Auth IngressAuthOptions `yaml:"auth,omitempty" json:"auth,omitempty"`
+
+	// Affinity is a set of options to route the requests of a client to the same upstream Service endpoint.
+	Affinity IngressAffinityOptions `yaml:"affinity,omitempty" json:"affinity,omitempty"`
+}
+
+type IngressAffinityOptions struct {
+	// Type is the session affinity type, only cookie is supported. No session affinity is configured if not set.
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// Cookie configures the cookie used for cookie affinity.
+	Cookie IngressAffinityCookieOptions `yaml:"cookie,omitempty" json:"cookie,omitempty"`
+}
+
+type IngressAffinityCookieOptions struct {
+	// Name is the name of the cookie holding the upstream endpoint. Required for cookie affinity.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Expires is the lifetime of the cookie in seconds. The cookie lasts for the browser session if not set.
+	Expires int `yaml:"expires,omitempty" json:"expires,omitempty"`
+}
+
+type IngressAuthOptions struct {
+	// Basic configures HTTP basic authentication.
+	Basic IngressBasicAuthOptions `yaml:"basic,omitempty" json:"basic,omitempty"`
+
+	// URL is the URL of an external authentication service, e.g. oauth2-proxy,
+	// to which each request is forwarded before being proxied to the upstream Service.
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Signin is the URL to redirect unauthenticated requests to.
+	Signin string `yaml:"signin,omitempty" json:"signin,omitempty"`
+
+	// ResponseHeaders is a list of headers of the authentication service response
+	// to pass to the upstream Service.
+	ResponseHeaders []string `yaml:"response_headers,omitempty" json:"response_headers,omitempty"`
+
+	// FromSpec only protects the paths whose operations have a security requirement in the spec,
+	// with the configured basic or external authentication matching their security schemes.
+	FromSpec bool `yaml:"from_spec,omitempty" json:"from_spec,omitempty"`
+
+	// Schemes maps the spec security schemes, by name, to the authentication protecting them with FromSpec,
+	// either basic or forward, i.e. the external authentication service. By default, HTTP basic schemes map to basic
+	// and the other ones, e.g. HTTP bearer or API key, to forward.
+	Schemes map[string]string `yaml:"schemes,omitempty" json:"schemes,omitempty"`
+}
+
+const (
+	AuthSchemeBasic   = "basic"
+	AuthSchemeForward = "forward"
+)
+
+type IngressBasicAuthOptions struct {
+	// Secret is the name of a Secret containing the htpasswd credentials,
+	// optionally prefixed by its namespace (namespace/name).
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+
+	// Realm is the message displayed in the authentication prompt.
+	Realm string `yaml:"realm,omitempty" json:"realm,omitempty"`
+}
+
+type IngressTLSOptions struct {
+	// SecretName is the name of a Secret that contains a TLS private key and certificate.
+	// TLS section is omitted from the generated resources if not set.
+	SecretName string `yaml:"secret_name,omitempty" json:"secret_name,omitempty"`
+
+	// Hosts is a list of hosts included in the TLS certificate. Defaults to the Ingress host.
+	Hosts []string `yaml:"hosts,omitempty" json:"hosts,omitempty"`
+}
+
+// GetIngressOpts returns the Ingress options for the given path and method.
+// Only Class, SSLRedirect, ProxyBodySize, Websocket, ProxyBuffering, WhitelistSourceRange, RequestHeaders and ResponseHeaders
+// can be overridden, non-empty operation-level values take precedence over path-level ones, which in turn take precedence
+// over the global ones. The headers are merged, the more specific level winning for a header set at several ones.
+// Health paths are routed to health.internal_class, if set, whatever their class.
+func (o *Options) GetIngressOpts(path, method string) IngressOptions {
+	ingressOpts := o.Ingress
+
+	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
+		ingressOpts = ingressOpts.override(pathSubOpts.Ingress)
+	}
+
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		ingressOpts = ingressOpts.override(opSubOpts.Ingress)
+	}
+
+	if o.IsPathHealth(path) && o.Health.InternalClass != "" {
+		ingressOpts.Class = o.Health.InternalClass
+	}
+
+	return ingressOpts
+}
+
+// override returns a copy of the options with the overridable options
+// replaced by the non-empty values of the given options.
+func (o IngressOptions) override(opts IngressOptions) IngressOptions {
+	if opts.Class != "" {
+		o.Class = opts.Class
+	}
+
+	if opts.SSLRedirect != nil {
+		o.SSLRedirect = opts.SSLRedirect
+	}
+
+	if opts.ProxyBodySize != "" {
+		o.ProxyBodySize = opts.ProxyBodySize
+	}
+
+	if opts.Websocket != nil {
+		o.Websocket = opts.Websocket
+	}
+
+	if opts.ProxyBuffering != "" {
+		o.ProxyBuffering = opts.ProxyBuffering
+	}
+
+	if len(opts.WhitelistSourceRange) > 0 {
+		o.WhitelistSourceRange = opts.WhitelistSourceRange
+	}
+
+	o.RequestHeaders = mergeHeaders(o.RequestHeaders, opts.RequestHeaders)
+	o.ResponseHeaders = mergeHeaders(o.ResponseHeaders, opts.ResponseHeaders)
+
+	return o
+}
+
+// mergeHeaders returns the headers with the overriding ones added, without modifying either
+func mergeHeaders(headers, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return headers
+	}
+
+	merged := make(map[string]string, len(headers)+len(overrides))
+	for name, value := range headers {
+		merged[name] = value
+	}
+
+	for name, value := range overrides {
+		merged[name] = value
+	}
+
+	return merged
+}
+
+func (o *IngressOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.ProxyBodySize, v.Match(proxyBodySizeRegex).Error(proxyBodySizeError)),
+		v.Field(&o.APIVersion, v.In("networking.k8s.io/v1", "networking.k8s.io/v1beta1").Error("ingress.api_version must be one of networking.k8s.io/v1 or networking.k8s.io/v1beta1")),
+		v.Field(&o.PathType, v.In("Exact", "Prefix", "ImplementationSpecific").Error("ingress.path_type must be one of Exact, Prefix or ImplementationSpecific")),
+		v.Field(&o.NameTemplate, v.By(validateTemplate)),
+		v.Field(&o.Labels, v.By(validateLabels("ingress.labels"))),
+		v.Field(&o.WhitelistSourceRange, v.Each(v.By(validateCIDR))),
+		v.Field(&o.Class, v.By(validateClass)),
+		v.Field(&o.ProxyBuffering, v.By(validateProxyBuffering)),
+		v.Field(&o.RequestHeaders, v.By(validateHeaders("ingress.request_headers"))),
+		v.Field(&o.ResponseHeaders, v.By(validateHeaders("ingress.response_headers"))),
+		v.Field(&o.StripSlash, v.When(o.AppendSlash, v.Empty.Error("ingress.append_slash and ingress.strip_slash can't be set together"))),
+		v.Field(&o.Controller,
+			v.In(IngressControllerNGINX, IngressControllerHAProxy, IngressControllerTraefik, IngressControllerALB).
+				Error("ingress.controller must be one of nginx, haproxy, traefik or alb"),
+		),
+	)
+}
+
+func validateProxyBuffering(value interface{}) error {
+	return v.Validate(value, v.In("on", "off").Error("ingress.proxy_buffering must be one of on or off"))
+}
+
+func (o *IngressAuthOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.URL, v.By(validateAuthURL("ingress.auth.url"))),
+		v.Field(&o.Signin, v.By(validateAuthURL("ingress.auth.signin"))),
+		v.Field(&o.FromSpec,
+			v.When(o.URL == "" && o.Basic.Secret == "",
+				v.Empty.Error("ingress.auth.from_spec requires ingress.auth.url or ingress.auth.basic.secret"),
+			),
+		),
+		v.Field(&o.Schemes, v.Each(
+			v.In(AuthSchemeBasic, AuthSchemeForward).Error("ingress.auth.schemes values must be basic or forward"),
+		)),
+	)
+}
+
+func (o *IngressBasicAuthOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Secret, v.When(o.Realm != "", v.Required.Error("ingress.auth.basic.secret is required when ingress.auth.basic.realm is set"))),
+	)
+}
+
+func (o *IngressAffinityOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Type, v.In("cookie").Error("ingress.affinity must be cookie")),
+		v.Field(&o.Cookie, v.By(o.validateCookie)),
+	)
+}
+
+func (o *IngressAffinityOptions) validateCookie(interface{}) error {
+	if o.Type == "cookie" && o.Cookie.Name == "" {
+		return fmt.Errorf("ingress.affinity.cookie.name is required when ingress.affinity is cookie")
+	}
+
+	if o.Cookie.Expires < 0 {
+		return fmt.Errorf("ingress.affinity.cookie.expires must not be negative")
+	}
+
+	return nil
+}
+
+const proxyBodySizeError = "ingress.proxy_body_size must be a number optionally suffixed by k, m or g"
+
+// validateAuthURL returns a rule checking that the given option, if set, is an absolute HTTP(S) URL
+func validateAuthURL(name string) v.RuleFunc {
+	return func(value interface{}) error {
+		s, _ := value.(string)
+		if s == "" {
+			return nil
+		}
+
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s must be an absolute http or https URL", name)
+		}
+
+		return nil
+	}
+}
+
+// validateLabels returns a rule checking that the keys and values of the given labels option are valid
+func validateLabels(name string) v.RuleFunc {
+	return func(value interface{}) error {
+		labels, _ := value.(map[string]string)
+
+		for key, value := range labels {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return fmt.Errorf("%s key %q is invalid: %s", name, key, strings.Join(errs, ", "))
+			}
+
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return fmt.Errorf("%s value %q of %q is invalid: %s", name, value, key, strings.Join(errs, ", "))
+			}
+		}
+
+		return nil
+	}
+}
+
+// validateHeaders returns a rule checking that the names of the given headers option are valid header names
+// and that the values fit on a single line
+func validateHeaders(name string) v.RuleFunc {
+	return func(value interface{}) error {
+		headers, _ := value.(map[string]string)
+
+		for header, headerValue := range headers {
+			if !headerNameRegex.MatchString(header) {
+				return fmt.Errorf("%s name %q is not a valid header name", name, header)
+			}
+
+			if strings.ContainsAny(headerValue, "\r\n") {
+				return fmt.Errorf("%s value of %q must not contain line breaks", name, header)
+			}
+		}
+
+		return nil
+	}
+}
+
+func validateClass(value interface{}) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+
+	if errs := validation.IsDNS1123Subdomain(s); len(errs) > 0 {
+		return fmt.Errorf("ingress.class %q is invalid: %s", s, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+func validateCIDR(value interface{}) error {
+	s, _ := value.(string)
+	if _, _, err := net.ParseCIDR(s); err != nil {
+		return fmt.Errorf("ingress.whitelist_source_range entry %q must be a valid CIDR, e.g. 10.0.0.0/24", s)
+	}
+
+	return nil
+}
+
+func validateTemplate(value interface{}) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+
+	if _, err := template.New("").Parse(s); err != nil {
+		return fmt.Errorf("ingress.name_template is not a valid template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package options
+
+type IstioOptions struct {
+	// Gateway is the name of an existing Istio Gateway to bind the VirtualService to.
+	Gateway string `yaml:"gateway,omitempty" json:"gateway,omitempty"`
+
+	// GenerateGateway additionally generates a Gateway bound to the Host.
+	GenerateGateway bool `yaml:"generate_gateway,omitempty" json:"generate_gateway,omitempty"`
+}
+
+func (o *IstioOptions) Validate() error {
+	return nil
+}
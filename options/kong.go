@@ -0,0 +1,13 @@
+package options
+
+type KongOptions struct {
+	// Plugins is a list of KongPlugin resource names to apply to the generated routes.
+	Plugins []string `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+
+	// StripPath instructs Kong to strip the matched route path before forwarding the request to the upstream Service.
+	StripPath bool `yaml:"strip_path,omitempty" json:"strip_path,omitempty"`
+}
+
+func (o *KongOptions) Validate() error {
+	return nil
+}
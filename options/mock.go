@@ -0,0 +1,47 @@
+package options
+
+import (
+	"fmt"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type MockOptions struct {
+	// Generate additionally generates a mock upstream Service, along with a Deployment running an echo server behind it,
+	// to test the routing without the actual backend.
+	Generate bool `yaml:"generate,omitempty" json:"generate,omitempty"`
+
+	// Image is the image of the mock server. Defaults to hashicorp/http-echo,
+	// answering each request with the Service name.
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+
+	// Port is the port the mock server listens on. Defaults to 5678, the hashicorp/http-echo one.
+	Port int32 `yaml:"port,omitempty" json:"port,omitempty"`
+}
+
+func (o *MockOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Port,
+			v.Min(int32(0)).Error("mock.port must be between 1 and 65535"),
+			v.Max(int32(65535)).Error("mock.port must be between 1 and 65535"),
+		),
+	)
+}
+
+// validateMock checks that the mock Service can replace the upstream one
+func (o *Options) validateMock() error {
+	if !o.Mock.Generate {
+		return nil
+	}
+
+	if o.Service.Generate {
+		return fmt.Errorf("mock.generate and service.generate both generate the upstream Service, set only one of them")
+	}
+
+	// a Service port can't be referenced by name before it exists
+	if o.Service.PortName != "" {
+		return fmt.Errorf("service.port_name can't be used with mock.generate, use service.port instead")
+	}
+
+	return nil
+}
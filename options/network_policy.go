@@ -0,0 +1,22 @@
+package options
+
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type NetworkPolicyOptions struct {
+	// Generate additionally generates a NetworkPolicy allowing traffic from the ingress controller to the upstream Service.
+	Generate bool `yaml:"generate,omitempty" json:"generate,omitempty"`
+
+	// PodSelector selects the pods of the upstream Service. Defaults to app=<service name>.
+	PodSelector map[string]string `yaml:"pod_selector,omitempty" json:"pod_selector,omitempty"`
+
+	// ControllerNamespace is the namespace of the ingress controller allowed to reach the pods.
+	ControllerNamespace string `yaml:"controller_namespace,omitempty" json:"controller_namespace,omitempty"`
+}
+
+func (o *NetworkPolicyOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.ControllerNamespace, v.Match(namespaceRegex).Error("network_policy.controller_namespace must be a valid RFC 1123 label")),
+	)
+}
@@ -1,26 +1,63 @@
 package options
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/go-logr/logr"
 	v "github.com/go-ozzo/ozzo-validation/v4"
 )
 
+var (
+	namespaceRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+	// hostRegex matches a DNS name, optionally prefixed by *. to match a single subdomain level, or * to match all hosts
+	hostRegex = regexp.MustCompile(`^(\*|(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*)$`)
+
+	// namePrefixRegex matches the start of an RFC 1123 label, which may end with a - to separate it from the name
+	namePrefixRegex = regexp.MustCompile(`^[a-z0-9][-a-z0-9]*$`)
+)
+
 // SubOptions allow user to overwrite certain options at path/operation level
 // using x-kusk extension
 type SubOptions struct {
 	Disabled *bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	GRPC     *bool `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+
+	// Health tags a path as a health path, which is left out of the public routes, see HealthOptions.
+	Health *bool `yaml:"health,omitempty" json:"health,omitempty"`
 
 	Host       string           `yaml:"host,omitempty" json:"host,omitempty"`
+	Path       PathOptions      `yaml:"path,omitempty" json:"path,omitempty"`
 	CORS       CORSOptions      `yaml:"cors,omitempty" json:"cors,omitempty"`
 	RateLimits RateLimitOptions `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
 	Timeouts   TimeoutOptions   `yaml:"timeouts,omitempty" json:"timeouts,omitempty"`
+	Retries    RetryOptions     `yaml:"retries,omitempty" json:"retries,omitempty"`
+	Ingress    IngressOptions   `yaml:"ingress,omitempty" json:"ingress,omitempty"`
+}
+
+// ServerOptions are the options set with the x-kusk extension of a spec server
+type ServerOptions struct {
+	// Environment tags the server with an environment, e.g. production, to select it with the environment option.
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
 }
 
 type Options struct {
 	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
 
-	// Namespace for the generated resource. Default value is "default".
+	// AllowEmpty lets the generation succeed without generating anything when no path is left to route,
+	// e.g. when all of them are disabled on purpose. It is an error otherwise, to catch such misconfigurations.
+	AllowEmpty bool `yaml:"allow_empty,omitempty" json:"allow_empty,omitempty"`
+
+	// Namespace for the generated resource. It is omitted from the resources when empty,
+	// leaving it to be set at apply time, e.g. with kubectl apply -n.
 	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
 
+	// GRPC marks the paths as served by gRPC, e.g. for gRPC-transcoded services, which can be overwritten at path level.
+	GRPC bool `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+
 	// Service is a set of options of a target service to receive traffic.
 	Service ServiceOptions `yaml:"service,omitempty" json:"service,omitempty"`
 
@@ -34,11 +71,58 @@ type Options struct {
 	// See https://kubernetes.io/docs/concepts/services-networking/ingress/#ingress-rules for additional documentation.
 	Host string `yaml:"host,omitempty" json:"host,omitempty"`
 
+	// HostTemplate is a Go text/template rendered against the options to set the host when it isn't set,
+	// e.g. {{.Service.Name}}.{{.Namespace}}.example.com to generate consistent hosts for many services.
+	HostTemplate string `yaml:"host_template,omitempty" json:"host_template,omitempty"`
+
+	// Environment selects the spec servers tagged with this environment in their x-kusk extension,
+	// e.g. production, to derive the hosts and base path from. All the servers are used if not set.
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
+
+	// NamePrefix is prepended to the names of the generated resources, e.g. tenant- to tell apart those of several tenants.
+	NamePrefix string `yaml:"name_prefix,omitempty" json:"name_prefix,omitempty"`
+
 	CORS CORSOptions `yaml:"cors,omitempty" json:"cors,omitempty"`
 
+	// Ingress is a set of generic Ingress resource options.
+	Ingress IngressOptions `yaml:"ingress,omitempty" json:"ingress,omitempty"`
+
 	// NGINXIngress is a set of custom nginx-ingress options.
 	NGINXIngress NGINXIngressOptions `yaml:"nginx_ingress,omitempty" json:"nginx_ingress,omitempty"`
 
+	// ALB is a set of custom AWS Load Balancer Controller options, applied with ingress.controller alb.
+	ALB ALBOptions `yaml:"alb,omitempty" json:"alb,omitempty"`
+
+	// Traefik is a set of custom Traefik options.
+	Traefik TraefikOptions `yaml:"traefik,omitempty" json:"traefik,omitempty"`
+
+	// Kong is a set of custom Kong options.
+	Kong KongOptions `yaml:"kong,omitempty" json:"kong,omitempty"`
+
+	// Istio is a set of custom Istio options.
+	Istio IstioOptions `yaml:"istio,omitempty" json:"istio,omitempty"`
+
+	// Contour is a set of custom Contour options.
+	Contour ContourOptions `yaml:"contour,omitempty" json:"contour,omitempty"`
+
+	// Gloo is a set of custom Gloo Edge options.
+	Gloo GlooOptions `yaml:"gloo,omitempty" json:"gloo,omitempty"`
+
+	// APISIX is a set of custom Apache APISIX options.
+	APISIX APISIXOptions `yaml:"apisix,omitempty" json:"apisix,omitempty"`
+
+	// Gateway is a set of custom Kubernetes Gateway API options.
+	Gateway GatewayOptions `yaml:"gateway,omitempty" json:"gateway,omitempty"`
+
+	// NetworkPolicy is a set of options to generate a NetworkPolicy for the upstream Service.
+	NetworkPolicy NetworkPolicyOptions `yaml:"network_policy,omitempty" json:"network_policy,omitempty"`
+
+	// Mock is a set of options to generate a mock upstream Service for testing the routing.
+	Mock MockOptions `yaml:"mock,omitempty" json:"mock,omitempty"`
+
+	// Health is a set of options to route the paths tagged as health paths.
+	Health HealthOptions `yaml:"health,omitempty" json:"health,omitempty"`
+
 	// PathSubOptions allow to overwrite specific subset of Options for a given path.
 	// They are filled during extension parsing, the map key is path.
 	PathSubOptions map[string]SubOptions `yaml:"-" json:"-"`
@@ -50,13 +134,25 @@ type Options struct {
 	RateLimits RateLimitOptions `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
 
 	Timeouts TimeoutOptions `yaml:"timeouts,omitempty" json:"timeouts,omitempty"`
+
+	Retries RetryOptions `yaml:"retries,omitempty" json:"retries,omitempty"`
+
+	// Logger receives the debug logs of the generation decisions, e.g. why a path was left out, at verbosity level 1.
+	// Nothing is logged if not set.
+	Logger logr.Logger `yaml:"-" json:"-"`
 }
 
-func (o *Options) fillDefaults() {
-	if o.Namespace == "" {
-		o.Namespace = "default"
+// GetLogger returns the logger of the generation decisions, discarding the logs if none is set
+func (o *Options) GetLogger() logr.Logger {
+	if o.Logger == nil {
+		return logr.Discard()
 	}
 
+	return o.Logger
+}
+
+// FillDefaults sets the options left unset to their default values
+func (o *Options) FillDefaults() {
 	if o.Path.Base == "" {
 		o.Path.Base = "/"
 	}
@@ -65,40 +161,187 @@ func (o *Options) fillDefaults() {
 		o.Cluster.ClusterDomain = "cluster.local"
 	}
 
-	if o.Service.Port == 0 {
+	if o.Service.Port == 0 && o.Service.PortName == "" {
 		o.Service.Port = 80
 	}
-}
 
-func (o *Options) Validate() error {
-	err := v.ValidateStruct(o,
-		v.Field(&o.Namespace, v.Required.Error("Target namespace is required")),
-	)
-
-	if err != nil {
-		return err
+	if o.Service.Canary.Port == 0 {
+		o.Service.Canary.Port = o.Service.Port
 	}
 
-	return nil
-}
+	o.CORS = o.CORS.withGRPCWebDefaults()
 
-func (o *Options) FillDefaultsAndValidate() error {
-	o.fillDefaults()
+	// the literal host takes precedence over the template, whose errors are reported by Validate
+	if o.Host == "" && o.HostTemplate != "" {
+		if host, err := o.renderHostTemplate(); err == nil {
+			o.Host = host
+		}
+	}
+}
 
+// Validate checks the options, including the nested ones, without modifying them.
+// Options relying on defaults, e.g. path.base, have to be filled with FillDefaults first.
+func (o *Options) Validate() error {
 	return v.Validate([]v.Validatable{
-		o,
+		validatableFunc(o.validate),
 		&o.Service,
+		&o.Service.Canary,
 		&o.Path,
 		&o.Cluster,
 		&o.CORS,
+		&o.Ingress,
+		&o.Ingress.Auth,
+		&o.Ingress.Auth.Basic,
+		&o.Ingress.Affinity,
 		&o.NGINXIngress,
+		&o.ALB,
+		&o.Traefik,
+		&o.Kong,
+		&o.Istio,
+		&o.Contour,
+		&o.Gloo,
+		&o.APISIX,
+		&o.Gateway,
+		&o.NetworkPolicy,
+		&o.Mock,
+		validatableFunc(o.validateMock),
+		&o.Health,
 		&o.RateLimits,
 		&o.Timeouts,
+		&o.Retries,
 	})
+}
+
+// validatableFunc adapts a validation function to v.Validatable
+type validatableFunc func() error
 
+func (f validatableFunc) Validate() error {
+	return f()
+}
+
+// validate checks the top-level options and the sub-options
+func (o *Options) validate() error {
+	err := v.ValidateStruct(o,
+		v.Field(&o.Namespace, v.Match(namespaceRegex).Error("namespace must be a valid RFC 1123 label")),
+		v.Field(&o.Host, v.Match(hostRegex).Error("host must be a valid DNS name, optionally prefixed by *. for a wildcard")),
+		v.Field(&o.HostTemplate, v.By(o.validateHostTemplate)),
+		v.Field(&o.NamePrefix,
+			v.Length(0, 62).Error("name_prefix must be at most 62 characters long, leaving room for the name"),
+			v.Match(namePrefixRegex).Error("name_prefix must consist of lower case alphanumeric characters or '-', and start with an alphanumeric character"),
+		),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	for path, pathSubOpts := range o.PathSubOptions {
+		if err := validateSubOptions(path, pathSubOpts); err != nil {
+			return err
+		}
+	}
+
+	for operation, opSubOpts := range o.OperationSubOptions {
+		if err := validateSubOptions(operation, opSubOpts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSubOptions checks the path and Ingress options which can be overridden at the path or operation level
+func validateSubOptions(key string, subOpts SubOptions) error {
+	if err := validatePathType(subOpts.Path.Type); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if err := validateBase(subOpts.Path.Base); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if err := subOpts.Timeouts.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if err := subOpts.CORS.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if size := subOpts.Ingress.ProxyBodySize; size != "" && !proxyBodySizeRegex.MatchString(size) {
+		return fmt.Errorf("%s: %s", key, proxyBodySizeError)
+	}
+
+	if err := validateClass(subOpts.Ingress.Class); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if err := validateProxyBuffering(subOpts.Ingress.ProxyBuffering); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	for _, cidr := range subOpts.Ingress.WhitelistSourceRange {
+		if err := validateCIDR(cidr); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if err := validateHeaders("ingress.request_headers")(subOpts.Ingress.RequestHeaders); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if err := validateHeaders("ingress.response_headers")(subOpts.Ingress.ResponseHeaders); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	return nil
+}
+
+// renderHostTemplate renders the host template against the options
+func (o *Options) renderHostTemplate() (string, error) {
+	tmpl, err := template.New("host").Option("missingkey=error").Parse(o.HostTemplate)
+	if err != nil {
+		return "", fmt.Errorf("host_template is not a valid template: %w", err)
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, o); err != nil {
+		return "", fmt.Errorf("failed to render host_template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+func (o *Options) validateHostTemplate(interface{}) error {
+	if o.HostTemplate == "" {
+		return nil
+	}
+
+	host, err := o.renderHostTemplate()
+	if err != nil {
+		return err
+	}
+
+	if !hostRegex.MatchString(host) {
+		return fmt.Errorf("host_template must render a valid DNS name, got %q", host)
+	}
+
+	return nil
+}
+
+// FillDefaultsAndValidate fills the unset options with their default values and validates the result
+func (o *Options) FillDefaultsAndValidate() error {
+	o.FillDefaults()
+
+	return o.Validate()
 }
 
 func (o *Options) IsOperationDisabled(path, method string) bool {
+	// filtered out paths and methods are left out regardless of their x-kusk extension
+	if o.IsPathFilteredOut(path) || !o.Path.includesMethod(method) {
+		return true
+	}
+
 	opSubOptions, ok := o.OperationSubOptions[method+path]
 
 	// If the operation has an explicit value set, return that (takes precedence over the path level setting)
@@ -111,6 +354,16 @@ func (o *Options) IsOperationDisabled(path, method string) bool {
 }
 
 func (o *Options) IsPathDisabled(path string) bool {
+	// filtered out paths are left out regardless of their x-kusk extension
+	if o.IsPathFilteredOut(path) {
+		return true
+	}
+
+	// health paths are kept out of the public routes, unless routed to an internal Ingress class
+	if o.IsPathHealth(path) && o.Health.InternalClass == "" {
+		return true
+	}
+
 	pathSubOptions, ok := o.PathSubOptions[path]
 
 	// If the path has an explicit value set, return that (takes precedence over the global level setting)
@@ -120,3 +373,23 @@ func (o *Options) IsPathDisabled(path string) bool {
 
 	return o.Disabled
 }
+
+// IsPathGRPC returns whether the path is served by gRPC,
+// the path level setting taking precedence over the global one
+func (o *Options) IsPathGRPC(path string) bool {
+	if pathSubOptions, ok := o.PathSubOptions[path]; ok && pathSubOptions.GRPC != nil {
+		return *pathSubOptions.GRPC
+	}
+
+	return o.GRPC
+}
+
+// IsPathFilteredOut returns whether the path matches an exclude pattern or,
+// when include patterns are set, none of them. Exclusion wins over inclusion.
+func (o *Options) IsPathFilteredOut(path string) bool {
+	if matchesAny(path, o.Path.Exclude) {
+		return true
+	}
+
+	return len(o.Path.Include) > 0 && !matchesAny(path, o.Path.Include)
+}
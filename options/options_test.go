@@ -0,0 +1,400 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathExclude(t *testing.T) {
+	falseValue := false
+
+	opts := Options{
+		Path: PathOptions{
+			Exclude: []string{"/healthz", "/internal/*", "/v*/metrics"},
+		},
+		PathSubOptions: map[string]SubOptions{
+			// exclusion takes precedence over the x-kusk extension
+			"/healthz": {Disabled: &falseValue},
+		},
+	}
+
+	testCases := []struct {
+		path     string
+		excluded bool
+	}{
+		{path: "/healthz", excluded: true},
+		{path: "/healthz/live", excluded: false},
+		{path: "/internal", excluded: false},
+		{path: "/internal/users", excluded: true},
+		{path: "/internal/users/{id}", excluded: true},
+		{path: "/internals/users", excluded: false},
+		{path: "/v1/metrics", excluded: true},
+		{path: "/v1/metrics/cpu", excluded: false},
+		{path: "/books", excluded: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.path, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(testCase.excluded, opts.IsPathDisabled(testCase.path))
+			r.Equal(testCase.excluded, opts.IsOperationDisabled(testCase.path, "GET"))
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{pattern: "/*", path: "/", matches: true},
+		{pattern: "/*", path: "/books", matches: true},
+		{pattern: "/*", path: "/books/{id}", matches: true},
+		{pattern: "/books/*", path: "/books", matches: false},
+		{pattern: "/books/*", path: "/books/{id}", matches: true},
+		{pattern: "/books/*", path: "/books/{id}/reviews", matches: true},
+		{pattern: "/books/*", path: "/authors/{id}", matches: false},
+		{pattern: "/v*/books", path: "/v1/books", matches: true},
+		{pattern: "/v*/books", path: "/v1/books/{id}", matches: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.pattern+" "+testCase.path, func(t *testing.T) {
+			require.Equal(t, testCase.matches, matchGlob(testCase.pattern, testCase.path))
+		})
+	}
+}
+
+func TestInvalidPathExclude(t *testing.T) {
+	opts := PathOptions{
+		Base:    "/",
+		Exclude: []string{"/internal/[a-"},
+	}
+
+	require.Error(t, opts.Validate())
+}
+
+func TestInvalidPathBase(t *testing.T) {
+	r := require.New(t)
+
+	opts := Options{
+		Path: PathOptions{
+			Base: "api",
+		},
+		Service: ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+	}
+
+	err := opts.FillDefaultsAndValidate()
+	r.Error(err)
+	r.Contains(err.Error(), `path.base "api" must be an absolute path starting with /, e.g. /api`)
+
+	opts.Path.Base = "/api"
+	opts.PathSubOptions = map[string]SubOptions{
+		"/books": {Path: PathOptions{Base: "v2"}},
+	}
+
+	err = opts.FillDefaultsAndValidate()
+	r.Error(err)
+	r.Contains(err.Error(), `/books: path.base "v2" must be an absolute path starting with /`)
+}
+
+func TestPathInclude(t *testing.T) {
+	opts := Options{
+		Path: PathOptions{
+			Include: []string{"/books", "/books/*", "/authors/*"},
+			Exclude: []string{"/books/internal/*"},
+		},
+	}
+
+	testCases := []struct {
+		path     string
+		disabled bool
+	}{
+		{path: "/books", disabled: false},
+		{path: "/books/{id}", disabled: false},
+		{path: "/authors/{id}/books", disabled: false},
+		// not explicitly disabled, but matching no include pattern
+		{path: "/authors", disabled: true},
+		{path: "/reviews", disabled: true},
+		// exclude wins over include
+		{path: "/books/internal/stats", disabled: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.path, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(testCase.disabled, opts.IsPathDisabled(testCase.path))
+			r.Equal(testCase.disabled, opts.IsOperationDisabled(testCase.path, "GET"))
+		})
+	}
+}
+
+func TestPathMethods(t *testing.T) {
+	falseValue := false
+
+	opts := Options{
+		Path: PathOptions{
+			Methods: []string{"GET", "HEAD"},
+		},
+		OperationSubOptions: map[string]SubOptions{
+			// the method filter takes precedence over the x-kusk extension
+			"POST/books": {Disabled: &falseValue},
+		},
+	}
+
+	testCases := []struct {
+		method   string
+		disabled bool
+	}{
+		{method: "GET", disabled: false},
+		{method: "HEAD", disabled: false},
+		{method: "POST", disabled: true},
+		{method: "DELETE", disabled: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.method, func(t *testing.T) {
+			require.Equal(t, testCase.disabled, opts.IsOperationDisabled("/books", testCase.method))
+		})
+	}
+}
+
+func TestInvalidPathMethods(t *testing.T) {
+	opts := PathOptions{
+		Base:    "/",
+		Methods: []string{"get"},
+	}
+
+	require.Error(t, opts.Validate())
+}
+
+func TestGetTimeoutOpts(t *testing.T) {
+	r := require.New(t)
+
+	opts := Options{
+		Timeouts: TimeoutOptions{
+			RequestTimeout: 30,
+			IdleTimeout:    60,
+		},
+		PathSubOptions: map[string]SubOptions{
+			"/reports": {Timeouts: TimeoutOptions{RequestTimeout: 300}},
+		},
+		OperationSubOptions: map[string]SubOptions{
+			"POST/reports": {Timeouts: TimeoutOptions{IdleTimeout: 120}},
+		},
+	}
+
+	r.Equal(TimeoutOptions{RequestTimeout: 30, IdleTimeout: 60}, opts.GetTimeoutOpts("/books", ""))
+	r.Equal(TimeoutOptions{RequestTimeout: 300, IdleTimeout: 60}, opts.GetTimeoutOpts("/reports", ""))
+	r.Equal(TimeoutOptions{RequestTimeout: 300, IdleTimeout: 120}, opts.GetTimeoutOpts("/reports", "POST"))
+}
+
+func TestFillDefaults(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     Options
+		expected Options
+	}{
+		{
+			name: "unset options",
+			opts: Options{},
+			expected: Options{
+				Path: PathOptions{
+					Base: "/",
+				},
+				Cluster: ClusterOptions{
+					ClusterDomain: "cluster.local",
+				},
+				Service: ServiceOptions{
+					Port: 80,
+					Canary: CanaryOptions{
+						Port: 80,
+					},
+				},
+			},
+		},
+		{
+			name: "set options are kept",
+			opts: Options{
+				Path: PathOptions{
+					Base: "/api",
+				},
+				Cluster: ClusterOptions{
+					ClusterDomain: "cluster.internal",
+				},
+				Service: ServiceOptions{
+					PortName: "http",
+					Canary: CanaryOptions{
+						Port: 8080,
+					},
+				},
+			},
+			expected: Options{
+				Path: PathOptions{
+					Base: "/api",
+				},
+				Cluster: ClusterOptions{
+					ClusterDomain: "cluster.internal",
+				},
+				Service: ServiceOptions{
+					PortName: "http",
+					Canary: CanaryOptions{
+						Port: 8080,
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := testCase.opts
+			opts.FillDefaults()
+
+			require.Equal(t, testCase.expected, opts)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Options{
+		Namespace: "default",
+		Host:      "example.org",
+		Path: PathOptions{
+			Base: "/",
+		},
+		Cluster: ClusterOptions{
+			ClusterDomain: "cluster.local",
+		},
+		Service: ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+			Port:      80,
+		},
+	}
+
+	t.Run("valid options are left unchanged", func(t *testing.T) {
+		r := require.New(t)
+
+		opts := valid
+		r.NoError(opts.Validate())
+		r.Equal(valid, opts)
+	})
+
+	testCases := []struct {
+		name   string
+		modify func(o *Options)
+	}{
+		{
+			name:   "defaults are not filled",
+			modify: func(o *Options) { o.Path.Base = "" },
+		},
+		{
+			name:   "invalid top-level option",
+			modify: func(o *Options) { o.Namespace = "Default" },
+		},
+		{
+			name:   "invalid nested option",
+			modify: func(o *Options) { o.Ingress.Auth.Basic.Realm = "Authentication Required" },
+		},
+		{
+			name: "invalid sub-option",
+			modify: func(o *Options) {
+				o.PathSubOptions = map[string]SubOptions{
+					"/uploads": {Ingress: IngressOptions{ProxyBodySize: "8 MB"}},
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := valid
+			testCase.modify(&opts)
+
+			require.Error(t, opts.Validate())
+		})
+	}
+}
+
+func TestHostTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		host     string
+		template string
+		expected string
+		valid    bool
+	}{
+		{
+			name:     "rendered",
+			template: "{{.Service.Name}}.{{.Namespace}}.example.com",
+			expected: "webapp.books.example.com",
+			valid:    true,
+		},
+		{
+			name:     "literal host takes precedence",
+			host:     "books.example.com",
+			template: "{{.Service.Name}}.{{.Namespace}}.example.com",
+			expected: "books.example.com",
+			valid:    true,
+		},
+		{
+			name:     "invalid template",
+			template: "{{.Service.Name}.example.com",
+		},
+		{
+			name:     "unknown field",
+			template: "{{.Service.Host}}.example.com",
+		},
+		{
+			name:     "invalid rendered host",
+			template: "{{.Service.Name}}_{{.Namespace}}.example.com",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			opts := Options{
+				Namespace:    "books",
+				Host:         testCase.host,
+				HostTemplate: testCase.template,
+				Service: ServiceOptions{
+					Namespace: "default",
+					Name:      "webapp",
+				},
+			}
+
+			err := opts.FillDefaultsAndValidate()
+			if !testCase.valid {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(testCase.expected, opts.Host)
+		})
+	}
+}
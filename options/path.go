@@ -1,6 +1,12 @@
 package options
 
 import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -21,10 +27,188 @@ type PathOptions struct {
 
 	// Split forces Kusk to generate a separate resource for each Path or Operation, where appropriate.
 	Split bool `yaml:"split,omitempty" json:"split,omitempty"`
+
+	// Default routes all the requests under Base to the upstream service when the spec declares no paths,
+	// e.g. to proxy a service whose spec only declares its servers. Such a spec is an error otherwise.
+	Default bool `yaml:"default,omitempty" json:"default,omitempty"`
+
+	// Exclude is a list of glob patterns of spec paths to leave out of the generated resources, e.g. /healthz.
+	// A pattern ending with /* also matches the nested subpaths, e.g. /internal/* matches /internal/users/{id}.
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+
+	// Include is a list of glob patterns, with the same syntax as Exclude, restricting the generated resources
+	// to the matching spec paths. All paths are included if not set. Exclude takes precedence over Include.
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+
+	// Methods is a list of HTTP methods, e.g. GET, restricting the generated resources to the operations
+	// with one of them. The paths without any such operation are left out. All methods are included if not set.
+	Methods []string `yaml:"methods,omitempty" json:"methods,omitempty"`
+
+	// Type forces the type of the Ingress paths generated for the path: Exact, Prefix or ImplementationSpecific,
+	// overriding the type chosen automatically and ingress.path_type.
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// VariableRegex is the regular expression matching the value of a path variable, e.g. [0-9]+ for numeric ids,
+	// in the regex paths generated for the paths with variables. Defaults to [^/]+, i.e. any path segment.
+	VariableRegex string `yaml:"variable_regex,omitempty" json:"variable_regex,omitempty"`
+}
+
+// GetPathOpts returns the path options for the given path and method.
+// Non-empty operation-level values take precedence over path-level ones,
+// which in turn take precedence over the global ones.
+func (o *Options) GetPathOpts(path, method string) PathOptions {
+	// take global path options
+	pathOpts := o.Path
+
+	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
+		pathOpts = pathOpts.override(pathSubOpts.Path)
+	}
+
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		pathOpts = pathOpts.override(opSubOpts.Path)
+	}
+
+	return pathOpts
+}
+
+// override returns a copy of the options with base, trim prefix, rewrite and type
+// replaced by the non-empty values of the given options.
+func (o PathOptions) override(opts PathOptions) PathOptions {
+	if opts.Base != "" {
+		o.Base = opts.Base
+	}
+
+	if opts.TrimPrefix != "" {
+		o.TrimPrefix = opts.TrimPrefix
+	}
+
+	if opts.Rewrite != "" {
+		o.Rewrite = opts.Rewrite
+	}
+
+	if opts.Type != "" {
+		o.Type = opts.Type
+	}
+
+	return o
 }
 
 func (o *PathOptions) Validate() error {
 	return validation.ValidateStruct(o,
-		validation.Field(&o.Base, validation.Required.Error("Base path required")),
+		validation.Field(&o.Base, validation.Required.Error("Base path required"), validation.By(validateBase)),
+		validation.Field(&o.Exclude, validation.Each(validation.By(validateGlob("path.exclude")))),
+		validation.Field(&o.Include, validation.Each(validation.By(validateGlob("path.include")))),
+		validation.Field(&o.Methods, validation.Each(
+			validation.In(
+				http.MethodGet,
+				http.MethodHead,
+				http.MethodPost,
+				http.MethodPut,
+				http.MethodPatch,
+				http.MethodDelete,
+				http.MethodOptions,
+				http.MethodTrace,
+			).Error("path.methods must be uppercase HTTP methods, e.g. GET"),
+		)),
+		validation.Field(&o.Type, validation.By(validatePathType)),
+		validation.Field(&o.VariableRegex, validation.By(validateVariableRegex)),
 	)
 }
+
+func validateVariableRegex(value interface{}) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return fmt.Errorf("path.variable_regex is not a valid regular expression: %w", err)
+	}
+
+	// the variables are rewritten by the position of their capture group
+	if re.NumSubexp() > 0 {
+		return fmt.Errorf("path.variable_regex must not contain capture groups, use non-capturing groups (?:...) instead")
+	}
+
+	return nil
+}
+
+// validateBase checks that the base path is absolute, as it is prepended to the spec paths
+func validateBase(value interface{}) error {
+	s, _ := value.(string)
+	if s != "" && !strings.HasPrefix(s, "/") {
+		return fmt.Errorf("path.base %q must be an absolute path starting with /, e.g. /%s", s, s)
+	}
+
+	return nil
+}
+
+func validatePathType(value interface{}) error {
+	return validation.Validate(value,
+		validation.In("Exact", "Prefix", "ImplementationSpecific").Error("path.type must be one of Exact, Prefix or ImplementationSpecific"),
+	)
+}
+
+// includesMethod returns whether the operations with the given method are included by the path.methods
+func (o *PathOptions) includesMethod(method string) bool {
+	if len(o.Methods) == 0 {
+		return true
+	}
+
+	for _, m := range o.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAny returns whether the spec path matches at least one of the glob patterns
+func matchesAny(specPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, specPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob reports whether the spec path matches the glob pattern, as per path.Match.
+// A pattern ending with /* also matches the nested subpaths, i.e. any path having a matching subpath followed by /.
+func matchGlob(pattern, specPath string) bool {
+	if ok, _ := path.Match(pattern, specPath); ok {
+		return true
+	}
+
+	if !strings.HasSuffix(pattern, "/*") {
+		return false
+	}
+
+	// the root subpath is empty, matched by the parent pattern of /*
+	parentPattern := strings.TrimSuffix(pattern, "/*")
+	for i := 0; i < len(specPath); i++ {
+		if specPath[i] != '/' {
+			continue
+		}
+
+		if ok, _ := path.Match(parentPattern, specPath[:i]); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateGlob(name string) validation.RuleFunc {
+	return func(value interface{}) error {
+		pattern, _ := value.(string)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s pattern %s is invalid: %w", name, pattern, err)
+		}
+
+		return nil
+	}
+}
@@ -1,6 +1,10 @@
 package options
 
-import "reflect"
+import (
+	"reflect"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
 
 type RateLimitOptions struct {
 	RPS   uint32 `json:"rps,omitempty" yaml:"rps,omitempty"`
@@ -19,7 +23,7 @@ func (o *Options) GetRateLimitOpts(path, method string) RateLimitOptions {
 	}
 
 	// if non-zero operation-level rate limit options are different, override them
-	if opSubOpts, ok := o.OperationSubOptions[path]; ok &&
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok &&
 		opSubOpts.RateLimits.ShouldOverride(rateLimitOpts) {
 		rateLimitOpts = opSubOpts.RateLimits
 	}
@@ -28,9 +32,11 @@ func (o *Options) GetRateLimitOpts(path, method string) RateLimitOptions {
 }
 
 func (o *RateLimitOptions) ShouldOverride(opts RateLimitOptions) bool {
-	return !reflect.DeepEqual(CORSOptions{}, o) && !reflect.DeepEqual(opts, o)
+	return !reflect.DeepEqual(RateLimitOptions{}, *o) && !reflect.DeepEqual(opts, *o)
 }
 
 func (o *RateLimitOptions) Validate() error {
-	return nil
+	return v.ValidateStruct(o,
+		v.Field(&o.RPS, v.When(o.Burst != 0, v.Required.Error("rate_limits.rps is required when rate_limits.burst is set"))),
+	)
 }
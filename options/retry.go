@@ -0,0 +1,49 @@
+package options
+
+import (
+	"reflect"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type RetryOptions struct {
+	// Attempts is the number of times a failed request is retried.
+	Attempts int `yaml:"attempts,omitempty" json:"attempts,omitempty"`
+	// PerTryTimeout is the timeout of each attempt (seconds).
+	PerTryTimeout uint32 `yaml:"per_try_timeout,omitempty" json:"per_try_timeout,omitempty"`
+	// ForceAll retries the requests of all the methods, including the non-idempotent ones such as POST,
+	// which are otherwise not retried as the failed request may have had an effect.
+	ForceAll bool `yaml:"force_all,omitempty" json:"force_all,omitempty"`
+}
+
+func (o *Options) GetRetryOpts(path, method string) RetryOptions {
+	// take global retry options
+	retryOpts := o.Retries
+
+	// if non-zero path-level retry options are different, override them
+	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
+		if !reflect.DeepEqual(RetryOptions{}, pathSubOpts.Retries) &&
+			!reflect.DeepEqual(retryOpts, pathSubOpts.Retries) {
+			retryOpts = pathSubOpts.Retries
+		}
+	}
+
+	// if non-zero operation-level retry options are different, override them
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		if !reflect.DeepEqual(RetryOptions{}, opSubOpts.Retries) &&
+			!reflect.DeepEqual(retryOpts, opSubOpts.Retries) {
+			retryOpts = opSubOpts.Retries
+		}
+	}
+
+	return retryOpts
+}
+
+func (o *RetryOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Attempts,
+			v.Min(0).Error("retries.attempts must not be negative"),
+			v.When(o.PerTryTimeout != 0, v.Required.Error("retries.attempts is required when retries.per_try_timeout is set")),
+		),
+	)
+}
@@ -1,9 +1,22 @@
 package options
 
 import (
+	"fmt"
+	"regexp"
+
 	v "github.com/go-ozzo/ozzo-validation/v4"
 )
 
+// portNameRegex matches an IANA service name, as required for Kubernetes port names
+var portNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+const (
+	ServiceProtocolHTTP  = "HTTP"
+	ServiceProtocolHTTPS = "HTTPS"
+	ServiceProtocolGRPC  = "GRPC"
+	ServiceProtocolGRPCS = "GRPCS"
+)
+
 type ServiceOptions struct {
 	// Namespace is the namespace containing the upstream Service.
 	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
@@ -11,14 +24,73 @@ type ServiceOptions struct {
 	// Name is the upstream Service's name.
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 
-	// Port is the upstream Service's port. Default value is 80.
+	// Port is the upstream Service's port. Default value is 80 unless PortName is set.
 	Port int32 `yaml:"port,omitempty" json:"port,omitempty"`
+
+	// PortName references the upstream Service's port by name instead of by number.
+	PortName string `yaml:"port_name,omitempty" json:"port_name,omitempty"`
+
+	// Canary is a second upstream Service receiving a share of the traffic, e.g. for progressive delivery.
+	Canary CanaryOptions `yaml:"canary,omitempty" json:"canary,omitempty"`
+
+	// Generate additionally generates the upstream Service, of type ClusterIP, for specs without an existing one.
+	Generate bool `yaml:"generate,omitempty" json:"generate,omitempty"`
+
+	// Selector selects the pods of the generated Service. Required when Generate is set.
+	Selector map[string]string `yaml:"selector,omitempty" json:"selector,omitempty"`
+
+	// Protocol is the protocol spoken by the upstream Service, one of HTTP, HTTPS, GRPC or GRPCS.
+	// Left to the ingress controller default, i.e. HTTP, if not set.
+	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty"`
+}
+
+type CanaryOptions struct {
+	// Name is the canary Service's name. No canary routing is set up if not set.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Port is the canary Service's port. Defaults to the upstream Service's port.
+	Port int32 `yaml:"port,omitempty" json:"port,omitempty"`
+
+	// Weight is the percentage of requests routed to the canary Service, from 0 to 100.
+	Weight int `yaml:"weight,omitempty" json:"weight,omitempty"`
+}
+
+// FQDN returns the fully qualified domain name of the upstream Service in a cluster with the given domain,
+// i.e. name.namespace.svc.<clusterDomain>
+func (o *ServiceOptions) FQDN(clusterDomain string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", o.Name, o.Namespace, clusterDomain)
 }
 
 func (o *ServiceOptions) Validate() error {
 	return v.ValidateStruct(o,
 		v.Field(&o.Namespace, v.Required.Error("service.namespace is required")),
 		v.Field(&o.Name, v.Required.Error("service.name is required")),
-		v.Field(&o.Port, v.Required.Error("service.port is required"), v.Min(1), v.Max(65535)),
+		v.Field(&o.Port,
+			v.When(o.PortName == "", v.Required.Error("service.port is required")),
+			v.Min(int32(1)).Error("service.port must be between 1 and 65535"),
+			v.Max(int32(65535)).Error("service.port must be between 1 and 65535"),
+		),
+		v.Field(&o.PortName,
+			v.When(o.Port != 0, v.Empty.Error("service.port and service.port_name are mutually exclusive")),
+			v.Length(1, 15).Error("service.port_name must be at most 15 characters"),
+			v.Match(portNameRegex).Error("service.port_name must consist of lowercase alphanumeric characters or '-'"),
+			// a Service port can't be referenced by name before it exists
+			v.When(o.Generate, v.Empty.Error("service.port_name can't be used with service.generate, use service.port instead")),
+		),
+		v.Field(&o.Selector,
+			v.When(o.Generate, v.Required.Error("service.selector is required when service.generate is set")),
+			v.By(validateLabels("service.selector")),
+		),
+		v.Field(&o.Protocol,
+			v.In(ServiceProtocolHTTP, ServiceProtocolHTTPS, ServiceProtocolGRPC, ServiceProtocolGRPCS).
+				Error("service.protocol must be one of HTTP, HTTPS, GRPC or GRPCS"),
+		),
+	)
+}
+
+func (o *CanaryOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Port, v.Min(int32(0)), v.Max(int32(65535))),
+		v.Field(&o.Weight, v.Min(0), v.Max(100).Error("service.canary.weight must be between 0 and 100")),
 	)
 }
@@ -1,6 +1,11 @@
 package options
 
-import "reflect"
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// maxTimeout is the longest timeout accepted (seconds), a day, which catches timeouts mistakenly given in milliseconds
+const maxTimeout = 24 * 60 * 60
 
 type TimeoutOptions struct {
 	// RequestTimeout is total request timeout
@@ -9,29 +14,40 @@ type TimeoutOptions struct {
 	IdleTimeout uint32 `yaml:"idle_timeout,omitempty" json:"idle_timeout,omitempty"`
 }
 
+// GetTimeoutOpts returns the timeout options for the given path and method, non-zero operation-level timeouts
+// taking precedence over path-level ones, which in turn take precedence over the global ones.
+// e.g. a path only setting request_timeout keeps the global idle_timeout.
 func (o *Options) GetTimeoutOpts(path, method string) TimeoutOptions {
 	// take global timeout options
 	timeoutOpts := o.Timeouts
 
-	// if non-zero path-level timeout options are different, override them
 	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
-		if !reflect.DeepEqual(TimeoutOptions{}, pathSubOpts.Timeouts) &&
-			!reflect.DeepEqual(timeoutOpts, pathSubOpts.Timeouts) {
-			timeoutOpts = pathSubOpts.Timeouts
-		}
+		timeoutOpts = timeoutOpts.override(pathSubOpts.Timeouts)
 	}
 
-	// if non-zero operation-level timeout options are different, override them
-	if opSubOpts, ok := o.OperationSubOptions[path]; ok {
-		if !reflect.DeepEqual(TimeoutOptions{}, opSubOpts.Timeouts) &&
-			!reflect.DeepEqual(timeoutOpts, opSubOpts.Timeouts) {
-			timeoutOpts = opSubOpts.Timeouts
-		}
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		timeoutOpts = timeoutOpts.override(opSubOpts.Timeouts)
 	}
 
 	return timeoutOpts
 }
 
+// override returns a copy of the options with the non-zero timeouts of the given options
+func (o TimeoutOptions) override(opts TimeoutOptions) TimeoutOptions {
+	if opts.RequestTimeout != 0 {
+		o.RequestTimeout = opts.RequestTimeout
+	}
+
+	if opts.IdleTimeout != 0 {
+		o.IdleTimeout = opts.IdleTimeout
+	}
+
+	return o
+}
+
 func (o *TimeoutOptions) Validate() error {
-	return nil
+	return v.ValidateStruct(o,
+		v.Field(&o.RequestTimeout, v.Max(uint32(maxTimeout)).Error("timeouts.request_timeout must be at most 86400 seconds")),
+		v.Field(&o.IdleTimeout, v.Max(uint32(maxTimeout)).Error("timeouts.idle_timeout must be at most 86400 seconds")),
+	)
 }
@@ -0,0 +1,25 @@
+package options
+
+import (
+	"regexp"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var traefikMiddlewareRefRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+type TraefikOptions struct {
+	// EntryPoints is a list of Traefik entry points the IngressRoute listens on. Default value is "web".
+	EntryPoints []string `yaml:"entrypoints,omitempty" json:"entrypoints,omitempty"`
+
+	// Middlewares is a list of references to existing Middleware resources, in the form of name or namespace/name,
+	// applied to every route after the middlewares generated by Kusk.
+	Middlewares []string `yaml:"middlewares,omitempty" json:"middlewares,omitempty"`
+}
+
+func (o *TraefikOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.EntryPoints, v.Each(v.Required)),
+		v.Field(&o.Middlewares, v.Each(v.Match(traefikMiddlewareRefRegex).Error("traefik.middlewares must be in the form of name or namespace/name"))),
+	)
+}
@@ -0,0 +1,51 @@
+package spec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+)
+
+var (
+	gzipMagicBytes = []byte{0x1f, 0x8b}
+
+	// a YAML or JSON spec always contains characters outside of the base64 alphabet, i.e. colons or braces
+	base64Regex = regexp.MustCompile(`^[A-Za-z0-9+/]+={0,2}$`)
+
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// decode transparently decodes base64 encoded and decompresses gzip compressed specs,
+// any other content is returned as is
+func decode(spec []byte) ([]byte, error) {
+	if stripped := whitespaceRegex.ReplaceAll(spec, nil); len(stripped) > 0 && base64Regex.Match(stripped) {
+		decoded := make([]byte, base64.StdEncoding.DecodedLen(len(stripped)))
+
+		n, err := base64.StdEncoding.Decode(decoded, stripped)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 encoded spec: %w", err)
+		}
+
+		spec = decoded[:n]
+	}
+
+	if bytes.HasPrefix(spec, gzipMagicBytes) {
+		reader, err := gzip.NewReader(bytes.NewReader(spec))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip compressed spec: %w", err)
+		}
+		defer reader.Close()
+
+		decompressed, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip compressed spec: %w", err)
+		}
+
+		spec = decompressed
+	}
+
+	return spec, nil
+}
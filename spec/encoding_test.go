@@ -0,0 +1,99 @@
+package spec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const encodedSpec = `openapi: 3.0.1
+info:
+  title: Encoded API
+  version: 1.0.0
+paths: {}
+`
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name  string
+		spec  []byte
+		error bool
+	}{
+		{
+			name: "plain",
+			spec: []byte(encodedSpec),
+		},
+		{
+			name: "gzip",
+			spec: gzipped(t, []byte(encodedSpec)),
+		},
+		{
+			name: "base64",
+			spec: []byte(base64.StdEncoding.EncodeToString([]byte(encodedSpec))),
+		},
+		{
+			name: "base64 with line breaks",
+			spec: []byte(base64.StdEncoding.EncodeToString([]byte(encodedSpec))[:20] + "\n" + base64.StdEncoding.EncodeToString([]byte(encodedSpec))[20:] + "\n"),
+		},
+		{
+			name: "base64 encoded gzip",
+			spec: []byte(base64.StdEncoding.EncodeToString(gzipped(t, []byte(encodedSpec)))),
+		},
+		{
+			name:  "malformed gzip",
+			spec:  append([]byte{0x1f, 0x8b}, []byte("not really gzip")...),
+			error: true,
+		},
+		{
+			name:  "malformed base64",
+			spec:  []byte("b3BlbmFwaTogMy4wLjE"),
+			error: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := NewParser(openapi3.NewLoader()).ParseFromReader(bytes.NewReader(testCase.spec))
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal("Encoded API", spec.Info.Title)
+		})
+	}
+}
+
+func TestReadCompressedFile(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "openapi.yaml.gz")
+	r.NoError(ioutil.WriteFile(path, gzipped(t, []byte(encodedSpec)), os.ModePerm))
+
+	b, err := ReadFromURIWithTimeout(time.Second)(nil, &url.URL{Path: path})
+	r.NoError(err)
+	r.Equal(encodedSpec, string(b))
+}
@@ -67,6 +67,32 @@ func TestGetOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "path level path and cors options set",
+			spec: &openapi3.T{
+				Paths: openapi3.Paths{
+					"/pet": &openapi3.PathItem{
+						ExtensionProps: openapi3.ExtensionProps{
+							Extensions: map[string]interface{}{
+								kuskExtensionKey: json.RawMessage(`{"path":{"base":"/v2"},"cors":{"origins":["http://foo.example"]}}`),
+							},
+						},
+					},
+				},
+			},
+			res: options.Options{
+				PathSubOptions: map[string]options.SubOptions{
+					"/pet": {
+						Path: options.PathOptions{
+							Base: "/v2",
+						},
+						CORS: options.CORSOptions{
+							Origins: []string{"http://foo.example"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -0,0 +1,67 @@
+package spec
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxRedirects is the number of redirects followed when fetching a remote spec
+const maxRedirects = 10
+
+// ReadFromURIWithTimeout returns a function for openapi3.Loader.ReadFromURIFunc
+// that fetches remote specs over http(s) with the given timeout and reads local ones from disk.
+// Base64 encoded and gzip compressed specs are decoded transparently.
+func ReadFromURIWithTimeout(timeout time.Duration) func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			return nil
+		},
+	}
+
+	return func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		b, err := read(client, location)
+		if err != nil {
+			return nil, err
+		}
+
+		return decode(b)
+	}
+}
+
+// read reads the raw spec from a local file or fetches it from a remote http(s) location
+func read(client *http.Client, location *url.URL) ([]byte, error) {
+	if location.Scheme == "" && location.Host == "" {
+		return ioutil.ReadFile(location.Path)
+	}
+
+	if location.Scheme != "http" && location.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported spec location %s: only http and https are supported", location)
+	}
+
+	resp, err := client.Get(location.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch spec from %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch spec from %s: unexpected response status %s", location, resp.Status)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec from %s: %w", location, err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,90 @@
+package spec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const fetchedSpec = `openapi: 3.0.1
+info:
+  title: Fetched API
+  version: 1.0.0
+paths: {}
+`
+
+func TestReadFromURIWithTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fetchedSpec))
+	})
+	mux.HandleFunc("/moved.yaml", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/openapi.yaml", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop.yaml", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop.yaml", http.StatusFound)
+	})
+	mux.HandleFunc("/slow.yaml", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(fetchedSpec))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		name  string
+		path  string
+		error bool
+	}{
+		{
+			name: "spec fetched",
+			path: "/openapi.yaml",
+		},
+		{
+			name: "redirect followed",
+			path: "/moved.yaml",
+		},
+		{
+			name:  "redirect loop",
+			path:  "/loop.yaml",
+			error: true,
+		},
+		{
+			name:  "not found",
+			path:  "/missing.yaml",
+			error: true,
+		},
+		{
+			name:  "timeout",
+			path:  "/slow.yaml",
+			error: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			loader := openapi3.NewLoader()
+			loader.ReadFromURIFunc = ReadFromURIWithTimeout(100 * time.Millisecond)
+
+			u, err := url.Parse(server.URL + testCase.path)
+			r.NoError(err)
+
+			spec, err := NewParser(loader).Parse(u.String())
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal("Fetched API", spec.Info.Title)
+		})
+	}
+}
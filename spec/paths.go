@@ -0,0 +1,64 @@
+package spec
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+// SortedPaths returns the spec paths sorted alphabetically,
+// iterating over them keeps the generated output stable between runs
+func SortedPaths(paths openapi3.Paths) []string {
+	res := make([]string, 0, len(paths))
+	for path := range paths {
+		res = append(res, path)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// DisableDeprecatedPaths disables the paths whose operations are all deprecated,
+// unless the path is explicitly enabled with its x-kusk extension.
+// Paths with a mix of deprecated and non-deprecated operations are kept.
+func DisableDeprecatedPaths(opts *options.Options, spec *openapi3.T) {
+	disabled := true
+
+	for path, pathItem := range spec.Paths {
+		if !isDeprecated(pathItem) {
+			continue
+		}
+
+		pathSubOptions := opts.PathSubOptions[path]
+		if pathSubOptions.Disabled != nil {
+			continue
+		}
+
+		pathSubOptions.Disabled = &disabled
+
+		if opts.PathSubOptions == nil {
+			opts.PathSubOptions = map[string]options.SubOptions{}
+		}
+
+		opts.PathSubOptions[path] = pathSubOptions
+	}
+}
+
+// isDeprecated returns whether the path has operations, all of them deprecated
+func isDeprecated(pathItem *openapi3.PathItem) bool {
+	operations := pathItem.Operations()
+	if len(operations) == 0 {
+		return false
+	}
+
+	for _, operation := range operations {
+		if !operation.Deprecated {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,63 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedPaths(t *testing.T) {
+	paths := openapi3.Paths{
+		"/pets/{petId}": &openapi3.PathItem{},
+		"/":             &openapi3.PathItem{},
+		"/pets":         &openapi3.PathItem{},
+		"/authors":      &openapi3.PathItem{},
+	}
+
+	require.Equal(t, []string{"/", "/authors", "/pets", "/pets/{petId}"}, SortedPaths(paths))
+	require.Empty(t, SortedPaths(openapi3.Paths{}))
+}
+
+func TestDisableDeprecatedPaths(t *testing.T) {
+	r := require.New(t)
+
+	spec, err := NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get: {}
+  /legacy/books:
+    get:
+      deprecated: true
+    post:
+      deprecated: true
+  /authors:
+    get:
+      deprecated: true
+    post: {}
+  /legacy/authors:
+    x-kusk:
+      disabled: false
+    get:
+      deprecated: true
+`))
+	r.NoError(err)
+
+	opts, err := GetOptions(spec)
+	r.NoError(err)
+
+	DisableDeprecatedPaths(opts, spec)
+
+	r.False(opts.IsPathDisabled("/books"))
+	// all operations deprecated
+	r.True(opts.IsPathDisabled("/legacy/books"))
+	// mixed deprecated and non-deprecated operations
+	r.False(opts.IsPathDisabled("/authors"))
+	// explicitly enabled
+	r.False(opts.IsPathDisabled("/legacy/authors"))
+}
@@ -0,0 +1,103 @@
+package spec
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk/options"
+)
+
+// ServerHosts returns a deduplicated list of hostnames found in the servers URLs,
+// in the order they are declared. Relative server URLs are skipped.
+func ServerHosts(servers openapi3.Servers) []string {
+	var hosts []string
+	seen := map[string]bool{}
+
+	for _, server := range servers {
+		u, err := parseServerURL(server)
+		if err != nil {
+			continue
+		}
+
+		host := u.Hostname()
+		if host == "" || seen[host] {
+			continue
+		}
+
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+
+	return hosts
+}
+
+// ServerBasePath returns the path of the servers URLs to be used as the base path of the API,
+// "/" if no servers are declared. If the servers declare different paths,
+// "/" is returned and ok is false.
+func ServerBasePath(servers openapi3.Servers) (base string, ok bool) {
+	for _, server := range servers {
+		u, err := parseServerURL(server)
+		if err != nil {
+			continue
+		}
+
+		path := strings.TrimSuffix(u.Path, "/")
+		if path == "" {
+			path = "/"
+		}
+
+		if base != "" && base != path {
+			return "/", false
+		}
+
+		base = path
+	}
+
+	if base == "" {
+		return "/", true
+	}
+
+	return base, true
+}
+
+// SelectEnvironmentServers keeps the spec servers tagged with the given environment in their x-kusk extension,
+// so that the hosts and base path are derived from them only. All the servers are kept if environment is empty.
+func SelectEnvironmentServers(spec *openapi3.T, environment string) error {
+	if environment == "" {
+		return nil
+	}
+
+	var servers openapi3.Servers
+	for _, server := range spec.Servers {
+		var serverOptions options.ServerOptions
+		if _, err := parseExtension(&server.ExtensionProps, &serverOptions); err != nil {
+			return fmt.Errorf("failed to extract server options of %s: %w", server.URL, err)
+		}
+
+		if serverOptions.Environment == environment {
+			servers = append(servers, server)
+		}
+	}
+
+	if len(servers) == 0 {
+		return fmt.Errorf("no spec server is tagged with the %s environment", environment)
+	}
+
+	spec.Servers = servers
+
+	return nil
+}
+
+// parseServerURL parses the server URL, substituting server variables with their default values
+func parseServerURL(server *openapi3.Server) (*url.URL, error) {
+	rawURL := server.URL
+
+	for name, variable := range server.Variables {
+		rawURL = strings.ReplaceAll(rawURL, "{"+name+"}", variable.Default)
+	}
+
+	return url.Parse(rawURL)
+}
@@ -0,0 +1,189 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerHosts(t *testing.T) {
+	testCases := []struct {
+		name    string
+		servers openapi3.Servers
+		res     []string
+	}{
+		{
+			name: "no servers",
+		},
+		{
+			name: "relative server url",
+			servers: openapi3.Servers{
+				{URL: "/api/v3"},
+			},
+		},
+		{
+			name: "multiple servers with duplicate hosts",
+			servers: openapi3.Servers{
+				{URL: "https://api.example.com/v1"},
+				{URL: "http://api.example.com:8080/v1"},
+				{URL: "https://staging.example.com"},
+			},
+			res: []string{"api.example.com", "staging.example.com"},
+		},
+		{
+			name: "server variables",
+			servers: openapi3.Servers{
+				{
+					URL: "https://{environment}.example.com/v1",
+					Variables: map[string]*openapi3.ServerVariable{
+						"environment": {Default: "api"},
+					},
+				},
+			},
+			res: []string{"api.example.com"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.res, ServerHosts(testCase.servers))
+		})
+	}
+}
+
+func TestServerBasePath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		servers openapi3.Servers
+		res     string
+		ok      bool
+	}{
+		{
+			name: "no servers",
+			res:  "/",
+			ok:   true,
+		},
+		{
+			name: "server without path",
+			servers: openapi3.Servers{
+				{URL: "https://api.example.com"},
+			},
+			res: "/",
+			ok:  true,
+		},
+		{
+			name: "server with sub path",
+			servers: openapi3.Servers{
+				{URL: "https://api.example.com/v2/"},
+			},
+			res: "/v2",
+			ok:  true,
+		},
+		{
+			name: "relative server url",
+			servers: openapi3.Servers{
+				{URL: "/api/v3"},
+			},
+			res: "/api/v3",
+			ok:  true,
+		},
+		{
+			name: "multiple servers with the same path",
+			servers: openapi3.Servers{
+				{URL: "https://api.example.com/v2"},
+				{URL: "http://staging.example.com:8080/v2/"},
+			},
+			res: "/v2",
+			ok:  true,
+		},
+		{
+			name: "multiple servers with different paths",
+			servers: openapi3.Servers{
+				{URL: "https://api.example.com/v2"},
+				{URL: "https://api.example.com/v3"},
+			},
+			res: "/",
+			ok:  false,
+		},
+		{
+			name: "server variables",
+			servers: openapi3.Servers{
+				{
+					URL: "https://api.example.com/{version}",
+					Variables: map[string]*openapi3.ServerVariable{
+						"version": {Default: "v1"},
+					},
+				},
+			},
+			res: "/v1",
+			ok:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			base, ok := ServerBasePath(testCase.servers)
+			r.Equal(testCase.res, base)
+			r.Equal(testCase.ok, ok)
+		})
+	}
+}
+
+func TestSelectEnvironmentServers(t *testing.T) {
+	testCases := []struct {
+		name        string
+		environment string
+		res         []string
+		error       bool
+	}{
+		{
+			name: "all servers without environment",
+			res:  []string{"api.example.com", "staging.example.com"},
+		},
+		{
+			name:        "tagged server",
+			environment: "production",
+			res:         []string{"api.example.com"},
+		},
+		{
+			name:        "unknown environment",
+			environment: "development",
+			error:       true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+servers:
+- url: https://api.example.com/v1
+  x-kusk:
+    environment: production
+- url: https://staging.example.com/v1
+  x-kusk:
+    environment: staging
+paths: {}
+`))
+			r.NoError(err)
+
+			err = SelectEnvironmentServers(spec, testCase.environment)
+			if testCase.error {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(testCase.res, ServerHosts(spec.Servers))
+		})
+	}
+}
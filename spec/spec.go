@@ -13,6 +13,9 @@ import (
 	"github.com/ghodss/yaml"
 )
 
+// defaultSwaggerScheme is the scheme of the Swagger 2.0 host when the spec doesn't declare any schemes
+const defaultSwaggerScheme = "https"
+
 // isSwagger tries to decode the spec header
 func isSwagger(spec []byte) bool {
 	// internal helper struct to help us differentiate
@@ -81,6 +84,11 @@ func (p Parser) ParseFromReader(contents io.Reader) (*openapi3.T, error) {
 		return nil, fmt.Errorf("could not read contents of api spec: %w", err)
 	}
 
+	spec, err = decode(spec)
+	if err != nil {
+		return nil, err
+	}
+
 	if isSwagger(spec) {
 		return parseSwagger(spec)
 	}
@@ -101,6 +109,11 @@ func parseSwagger(spec []byte) (*openapi3.T, error) {
 		return nil, fmt.Errorf("failed to unmarshal Swagger: %w", err)
 	}
 
+	// without schemes, the host would be converted into a server URL without a valid scheme, e.g. https://://example.com
+	if swaggerSpec.Host != "" && len(swaggerSpec.Schemes) == 0 {
+		swaggerSpec.Schemes = []string{defaultSwaggerScheme}
+	}
+
 	return openapi2conv.ToV3(&swaggerSpec)
 }
 
@@ -144,3 +144,47 @@ paths:
 
 	}
 }
+
+func TestParseSwaggerServers(t *testing.T) {
+	testCases := []struct {
+		name    string
+		schemes string
+		res     []string
+	}{
+		{
+			name: "default scheme",
+			res:  []string{"https://books.example.com/api"},
+		},
+		{
+			name:    "schemes",
+			schemes: "schemes: [http, https]\n",
+			res:     []string{"http://books.example.com/api", "https://books.example.com/api"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			actual, err := Parser{loader: openapi3.NewLoader()}.ParseFromReader(strings.NewReader(`swagger: "2.0"
+info:
+  title: Books
+  version: 1.0.0
+host: books.example.com
+basePath: /api
+` + testCase.schemes + `paths:
+  /books:
+    get: {}
+`))
+			r.NoError(err)
+
+			var urls []string
+			for _, server := range actual.Servers {
+				urls = append(urls, server.URL)
+			}
+			r.Equal(testCase.res, urls)
+		})
+	}
+}